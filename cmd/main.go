@@ -2,16 +2,21 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/jetbuild/engine/internal/catalog"
 	"github.com/jetbuild/engine/internal/config"
 	"github.com/jetbuild/engine/internal/github"
 	"github.com/jetbuild/engine/internal/handler"
+	"github.com/jetbuild/engine/internal/k8s"
 	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
 	"github.com/jetbuild/engine/internal/vault"
 	"github.com/jetbuild/engine/pkg/flow"
 )
@@ -37,18 +42,66 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	v, err := vault.New(ctx, c.VaultAddr, c.VaultToken, c.VaultEngine, c.VaultEngineDescription)
+	var v *vault.Client
+	if c.StorageBackend == storage.BackendVault || len(c.VaultTransitKey) > 0 {
+		if v, err = newVault(ctx, &c); err != nil {
+			slog.Error("failed to connect vault", "error", err)
+			os.Exit(1)
+		}
+		defer v.Close()
+	}
+
+	clusters, flows, err := newRepositories(ctx, &c, v)
+	if err != nil {
+		slog.Error("failed to create storage", "backend", c.StorageBackend, "error", err)
+		os.Exit(1)
+	}
+
+	gh, err := newGitHub(&c)
 	if err != nil {
-		slog.Error("failed to connect vault", "error", err)
+		slog.Error("failed to create github client", "error", err)
+		os.Exit(1)
+	}
+
+	concurrency, err := strconv.Atoi(c.CatalogConcurrency)
+	if err != nil {
+		slog.Error("failed to parse catalog concurrency", "error", err)
+		os.Exit(1)
+	}
+
+	statusConcurrency, err := strconv.Atoi(c.StatusConcurrency)
+	if err != nil {
+		slog.Error("failed to parse status concurrency", "error", err)
 		os.Exit(1)
 	}
 
 	h := handler.Handler{
 		Validator:         validator.New(validator.WithRequiredStructEnabled()),
-		ClusterRepository: vault.NewRepository[model.Cluster](v, "clusters"),
-		FlowRepository:    vault.NewRepository[flow.Flow](v, "flows"),
+		ClusterRepository: clusters,
+		FlowRepository:    flows,
 		Config:            &c,
-		GitHub:            github.New(c.GithubOrganization),
+		GitHub:            gh,
+		Catalog:           catalog.New(gh, flows, concurrency),
+		StatusConcurrency: statusConcurrency,
+	}
+
+	ttl, err := config.PositiveDuration(c.ClusterClientIdleTTL)
+	if err != nil {
+		slog.Error("failed to parse cluster client idle ttl duration", "error", err)
+		os.Exit(1)
+	}
+
+	h.Clients = k8s.NewPool(ttl)
+
+	if len(c.VaultTransitKey) > 0 {
+		h.Transit = vault.NewTransit(v, c.VaultTransitEngine, c.VaultTransitKey)
+	}
+
+	if len(c.InClusterName) > 0 {
+		if err = h.RegisterInCluster(ctx, c.InClusterName); err != nil {
+			slog.Error("failed to register in-cluster cluster", "error", err)
+			os.Exit(1)
+		}
 	}
 
 	t, err := h.GitHub.GetRepositoryLatestTag(ctx, "runner")
@@ -64,3 +117,63 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func newVault(ctx context.Context, c *config.Config) (*vault.Client, error) {
+	auth, err := vault.NewAuth(c.VaultAuthMethod, c.VaultAuthMount, c.VaultToken, c.VaultAppRoleID,
+		c.VaultAppRoleSecretID, c.VaultKubernetesRole, c.VaultKubernetesJWTPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return vault.New(ctx, c.VaultAddr, auth)
+}
+
+func newGitHub(c *config.Config) (github.GitHub, error) {
+	auth, err := github.NewAuth(c.GithubAuthMethod, c.GithubToken, c.GithubAppID, c.GithubInstallationID,
+		c.GithubAppKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return github.New(c.GithubOrganization, auth, github.Options{
+		BaseURL:   c.GithubBaseURL,
+		UploadURL: c.GithubUploadURL,
+		CAPath:    c.GithubCAPath,
+		ProxyURL:  c.GithubProxyURL,
+	})
+}
+
+func newRepositories(ctx context.Context, c *config.Config, v *vault.Client) (storage.Storage[model.Cluster], storage.Storage[flow.Flow], error) {
+	switch c.StorageBackend {
+	case storage.BackendVault:
+		if err := v.MountEngine(ctx, c.VaultEngine, c.VaultEngineDescription); err != nil {
+			return nil, nil, fmt.Errorf("failed to mount vault engine: %w", err)
+		}
+
+		if err := vault.Migrate[model.Cluster](ctx, v, "clusters"); err != nil {
+			return nil, nil, fmt.Errorf("failed to migrate clusters: %w", err)
+		}
+
+		if err := vault.Migrate[flow.Flow](ctx, v, "flows"); err != nil {
+			return nil, nil, fmt.Errorf("failed to migrate flows: %w", err)
+		}
+
+		return vault.NewRepository[model.Cluster](v, "clusters"), vault.NewRepository[flow.Flow](v, "flows"), nil
+	case storage.BackendFile:
+		clusters, err := storage.NewFile[model.Cluster](c.StoragePath, "clusters")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		flows, err := storage.NewFile[flow.Flow](c.StoragePath, "flows")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return clusters, flows, nil
+	case storage.BackendMemory:
+		return storage.NewMemory[model.Cluster](), storage.NewMemory[flow.Flow](), nil
+	default:
+		return nil, nil, fmt.Errorf("storage backend '%s' is not supported", c.StorageBackend)
+	}
+}
@@ -0,0 +1,396 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jetbuild/engine/internal/github"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+	"github.com/jetbuild/engine/pkg/flow"
+	"gopkg.in/yaml.v3"
+)
+
+const repositorySuffix = "-component"
+
+// Catalog holds the components found in the github organization. Loads build a new snapshot which is
+// swapped atomically, so readers never see a partially loaded catalog.
+type Catalog struct {
+	github      github.GitHub
+	flows       storage.Storage[flow.Flow]
+	concurrency int
+
+	// mu serializes the loads, which merge into the latest snapshot
+	mu       sync.Mutex
+	snapshot atomic.Pointer[snapshot]
+}
+
+type snapshot struct {
+	entries    []entry
+	components []model.Component
+	errors     []model.ComponentError
+}
+
+type entry struct {
+	repo      string
+	ref       string
+	component model.Component
+}
+
+// New returns an empty catalog, which fetches at most concurrency specs at once.
+func New(gh github.GitHub, flows storage.Storage[flow.Flow], concurrency int) *Catalog {
+	return &Catalog{
+		github:      gh,
+		flows:       flows,
+		concurrency: max(concurrency, 1),
+	}
+}
+
+// Components returns the components of the latest snapshot, which must not be modified.
+func (c *Catalog) Components() []model.Component {
+	s := c.snapshot.Load()
+	if s == nil {
+		return nil
+	}
+
+	return s.components
+}
+
+// Versions returns every version of the component, from the highest to the lowest.
+func (c *Catalog) Versions(key string) []model.Component {
+	var versions []model.Component
+	for _, component := range c.Components() {
+		if component.Key == key {
+			versions = append(versions, component)
+		}
+	}
+
+	model.SortComponentVersions(versions)
+
+	return versions
+}
+
+// Errors returns the components of the latest snapshot which could not be loaded.
+func (c *Catalog) Errors() []model.ComponentError {
+	s := c.snapshot.Load()
+	if s == nil {
+		return nil
+	}
+
+	return s.errors
+}
+
+// Load loads every component repository of the organization. A ref which cannot be loaded keeps its
+// component from the previous snapshot, and is still reported as an error.
+func (c *Catalog) Load(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	refs, failures, err := c.refs(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	if len(refs) == 0 && len(failures) == 0 {
+		return fmt.Errorf("could not find a component repository on '%s' github org", c.github.GetOrganizationName())
+	}
+
+	entries, fetchFailures := c.fetch(ctx, refs)
+	failures = append(failures, fetchFailures...)
+	entries = append(entries, c.kept(failures)...)
+
+	c.swap(entries, failures)
+
+	return nil
+}
+
+// Reload loads only the given repository, keeping the components of the others.
+func (c *Catalog) Reload(ctx context.Context, repo string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	refs, failures, err := c.refs(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	entries, fetchFailures := c.fetch(ctx, refs)
+	failures = append(failures, fetchFailures...)
+	entries = append(entries, c.kept(failures)...)
+
+	if s := c.snapshot.Load(); s != nil {
+		for _, e := range s.entries {
+			if e.repo != repo {
+				entries = append(entries, e)
+			}
+		}
+
+		for _, f := range s.errors {
+			if f.Repository != repo {
+				failures = append(failures, f)
+			}
+		}
+	}
+
+	c.swap(entries, failures)
+
+	return nil
+}
+
+// Run loads the catalog on every interval until the context is done. A load which fails entirely keeps the
+// previous catalog, and a load which fails for some refs keeps their previous components.
+func (c *Catalog) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := c.Load(ctx); err != nil {
+				slog.Error("failed to refresh component catalog", "error", err)
+
+				continue
+			}
+
+			slog.Info("component catalog refreshed",
+				slog.Int("components", len(c.Components())),
+				slog.Int("errors", len(c.Errors())),
+			)
+		}
+	}
+}
+
+// IsComponentRepository reports whether the repository name is the one of a component, which is how
+// events of unrelated repositories are ignored.
+func IsComponentRepository(name string) bool {
+	return strings.HasSuffix(name, repositorySuffix)
+}
+
+// refs returns the refs to load by repository, which are the release tags of every component repository
+// plus the versions used by stored flows. When only is set, the other repositories are skipped. A repository
+// whose release tags cannot be listed is reported as an error, and only the versions used by flows are loaded.
+func (c *Catalog) refs(ctx context.Context, only string) (map[string][]string, []model.ComponentError, error) {
+	flows, err := c.flows.List(ctx)
+	if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+		return nil, nil, fmt.Errorf("failed to list flows from storage: %w", err)
+	}
+
+	refs := make(map[string][]string)
+	add := func(repo, ref string) {
+		if !slices.Contains(refs[repo], ref) {
+			refs[repo] = append(refs[repo], ref)
+		}
+	}
+
+	for _, f := range flows {
+		for _, component := range f.Components {
+			repo := component.Key + repositorySuffix
+			if len(only) > 0 && repo != only {
+				continue
+			}
+
+			add(repo, "v"+component.Version)
+		}
+	}
+
+	org := c.github.GetOrganizationName()
+
+	var repos []string
+	if len(only) == 0 {
+		list, lErr := c.github.ListRepositories(ctx)
+		if lErr != nil {
+			return nil, nil, fmt.Errorf("failed to list github component repositories by '%s' org: %w", org, lErr)
+		}
+
+		for _, repo := range list {
+			if c.isComponent(repo.GetName(), repo.Topics) {
+				repos = append(repos, repo.GetName())
+			}
+		}
+	} else {
+		repo, gErr := c.github.GetRepository(ctx, only)
+		if gErr != nil && !errors.Is(gErr, github.ErrNotFound) {
+			return nil, nil, fmt.Errorf("failed to get github '%s' org '%s' repository: %w", org, only, gErr)
+		}
+
+		if repo != nil && c.isComponent(repo.GetName(), repo.Topics) {
+			repos = append(repos, repo.GetName())
+		}
+	}
+
+	var failures []model.ComponentError
+
+	// every release of a repository is served, and main only until the first release
+	for _, repo := range repos {
+		tags, tErr := c.github.ListRepositoryReleaseTags(ctx, repo)
+		if tErr != nil {
+			slog.Warn("failed to list component release tags", "repository", repo, "error", tErr)
+
+			failures = append(failures, model.ComponentError{
+				Repository: repo,
+				Error:      fmt.Sprintf("failed to list github '%s' org '%s' repository release tags: %s", org, repo, tErr),
+			})
+
+			continue
+		}
+
+		if len(tags) == 0 {
+			add(repo, "main")
+		}
+
+		for _, tag := range tags {
+			add(repo, tag)
+		}
+	}
+
+	return refs, failures, nil
+}
+
+func (c *Catalog) isComponent(name string, topics []string) bool {
+	return IsComponentRepository(name) && slices.Contains(topics, c.github.GetOrganizationName()+repositorySuffix)
+}
+
+type job struct {
+	repo string
+	ref  string
+}
+
+// fetch loads the spec of every ref with bounded concurrency. A spec which cannot be loaded is reported
+// as an error of its component instead of failing the others.
+func (c *Catalog) fetch(ctx context.Context, refs map[string][]string) ([]entry, []model.ComponentError) {
+	repos := make([]string, 0, len(refs))
+	for repo := range refs {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	var jobs []job
+	for _, repo := range repos {
+		for _, ref := range refs[repo] {
+			jobs = append(jobs, job{repo: repo, ref: ref})
+		}
+	}
+
+	// results are indexed by job, which keeps the order deterministic
+	components := make([]*model.Component, len(jobs))
+	errs := make([]error, len(jobs))
+
+	sem := make(chan struct{}, c.concurrency)
+
+	var wg sync.WaitGroup
+	for i, j := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, j job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			components[i], errs[i] = c.spec(ctx, j)
+		}(i, j)
+	}
+	wg.Wait()
+
+	var entries []entry
+	var failures []model.ComponentError
+
+	for i, j := range jobs {
+		if errs[i] != nil {
+			slog.Warn("failed to load component", "repository", j.repo, "ref", j.ref, "error", errs[i])
+
+			failures = append(failures, model.ComponentError{
+				Repository: j.repo,
+				Ref:        j.ref,
+				Error:      errs[i].Error(),
+			})
+
+			continue
+		}
+
+		entries = append(entries, entry{
+			repo:      j.repo,
+			ref:       j.ref,
+			component: *components[i],
+		})
+	}
+
+	return entries, failures
+}
+
+func (c *Catalog) spec(ctx context.Context, j job) (*model.Component, error) {
+	r, err := c.github.GetRepositoryContent(ctx, j.repo, j.ref, "spec.yml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get component spec file content: %w", err)
+	}
+
+	var component model.Component
+
+	if err = yaml.NewDecoder(r).Decode(&component); err != nil {
+		return nil, fmt.Errorf("failed to decode component spec file content: %w", err)
+	}
+
+	if err = component.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate component spec file content: %w", err)
+	}
+
+	return &component, nil
+}
+
+// kept returns the entries of the previous snapshot whose refs failed to load now, or whose repository
+// failed as a whole, so that a transient failure does not drop a component which was served before.
+func (c *Catalog) kept(failures []model.ComponentError) []entry {
+	s := c.snapshot.Load()
+	if s == nil {
+		return nil
+	}
+
+	var entries []entry
+	for _, e := range s.entries {
+		if slices.ContainsFunc(failures, func(f model.ComponentError) bool {
+			return f.Repository == e.repo && (len(f.Ref) == 0 || f.Ref == e.ref)
+		}) {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// swap publishes the entries as the new snapshot, keeping the first entry of each component version.
+func (c *Catalog) swap(entries []entry, failures []model.ComponentError) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].repo < entries[j].repo
+	})
+
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].Repository < failures[j].Repository
+	})
+
+	s := snapshot{
+		errors: failures,
+	}
+	seen := make(map[string]bool)
+
+	for _, e := range entries {
+		if seen[e.component.Key+e.component.Version] {
+			continue
+		}
+
+		seen[e.component.Key+e.component.Version] = true
+
+		s.entries = append(s.entries, e)
+		s.components = append(s.components, e.component)
+	}
+
+	c.snapshot.Store(&s)
+}
@@ -4,17 +4,46 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"time"
 )
 
 type Config struct {
 	ServerAddr             string `env:"SERVER_ADDR"`
 	ServerRoutePrefix      string `env:"SERVER_ROUTE_PREFIX"`
 	ServerInitTimeout      string `env:"SERVER_INIT_TIMEOUT"`
-	VaultAddr              string `env:"VAULT_ADDR"`
-	VaultEngine            string `env:"VAULT_ENGINE"`
-	VaultToken             string `env:"VAULT_TOKEN"`
-	VaultEngineDescription string `env:"VAULT_ENGINE_DESCRIPTION"`
+	ServerTrustedProxies   string `env:"SERVER_TRUSTED_PROXIES" default:""`
+	ServerAuthorHeader     string `env:"SERVER_AUTHOR_HEADER" default:""`
+	StorageBackend         string `env:"STORAGE_BACKEND" default:"vault"`
+	StoragePath            string `env:"STORAGE_PATH" default:"data"`
+	VaultAddr              string `env:"VAULT_ADDR" default:""`
+	VaultEngine            string `env:"VAULT_ENGINE" default:""`
+	VaultToken             string `env:"VAULT_TOKEN" default:""`
+	VaultEngineDescription string `env:"VAULT_ENGINE_DESCRIPTION" default:""`
+	VaultAuthMethod        string `env:"VAULT_AUTH_METHOD" default:"token"`
+	VaultAuthMount         string `env:"VAULT_AUTH_MOUNT" default:""`
+	VaultAppRoleID         string `env:"VAULT_APPROLE_ROLE_ID" default:""`
+	VaultAppRoleSecretID   string `env:"VAULT_APPROLE_SECRET_ID" default:""`
+	VaultKubernetesRole    string `env:"VAULT_KUBERNETES_ROLE" default:""`
+	VaultKubernetesJWTPath string `env:"VAULT_KUBERNETES_JWT_PATH" default:"/var/run/secrets/kubernetes.io/serviceaccount/token"`
+	VaultTransitEngine     string `env:"VAULT_TRANSIT_ENGINE" default:"transit"`
+	VaultTransitKey        string `env:"VAULT_TRANSIT_KEY" default:""`
 	GithubOrganization     string `env:"GITHUB_ORGANIZATION"`
+	GithubAuthMethod       string `env:"GITHUB_AUTH_METHOD" default:"none"`
+	GithubToken            string `env:"GITHUB_TOKEN" default:""`
+	GithubAppID            string `env:"GITHUB_APP_ID" default:""`
+	GithubInstallationID   string `env:"GITHUB_APP_INSTALLATION_ID" default:""`
+	GithubAppKeyPath       string `env:"GITHUB_APP_PRIVATE_KEY_PATH" default:""`
+	GithubBaseURL          string `env:"GITHUB_BASE_URL" default:""`
+	GithubUploadURL        string `env:"GITHUB_UPLOAD_URL" default:""`
+	GithubCAPath           string `env:"GITHUB_CA_PATH" default:""`
+	GithubProxyURL         string `env:"GITHUB_PROXY_URL" default:""`
+	GithubWebhookSecret    string `env:"GITHUB_WEBHOOK_SECRET" default:""`
+	CatalogRefreshInterval string `env:"CATALOG_REFRESH_INTERVAL" default:"10m"`
+	CatalogConcurrency     string `env:"CATALOG_CONCURRENCY" default:"8"`
+	ReconcileInterval      string `env:"RECONCILE_INTERVAL" default:"1m"`
+	StatusConcurrency      string `env:"STATUS_CONCURRENCY" default:"8"`
+	InClusterName          string `env:"IN_CLUSTER_NAME" default:""`
+	ClusterClientIdleTTL   string `env:"CLUSTER_CLIENT_IDLE_TTL" default:"10m"`
 }
 
 func (c *Config) Load() error {
@@ -29,6 +58,9 @@ func (c *Config) Load() error {
 		}
 
 		env, ok := os.LookupEnv(key)
+		if !ok {
+			env, ok = f.Tag.Lookup("default")
+		}
 		if !ok {
 			return fmt.Errorf("environment variable '%s' does not exist", key)
 		}
@@ -47,3 +79,17 @@ func (c *Config) Load() error {
 
 	return nil
 }
+
+// PositiveDuration parses the duration of a setting, which is used as an interval and so must be greater than zero.
+func PositiveDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("duration '%s' should be greater than zero", s)
+	}
+
+	return d, nil
+}
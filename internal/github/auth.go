@@ -0,0 +1,216 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+)
+
+const (
+	AuthMethodNone  = "none"
+	AuthMethodToken = "token"
+	AuthMethodApp   = "app"
+
+	// appTokenLeeway renews installation tokens before they expire, as they live for an hour.
+	appTokenLeeway = 5 * time.Minute
+)
+
+// Auth authenticates the requests of a client.
+type Auth interface {
+	authenticate(c *github.Client) *github.Client
+}
+
+type noneAuth struct{}
+
+type tokenAuth struct {
+	token string
+}
+
+type appAuth struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+}
+
+// NewAuth returns the auth of the given method. The app auth signs its tokens with the private key read
+// from the path.
+func NewAuth(method, token, appID, installationID, privateKeyPath string) (Auth, error) {
+	switch method {
+	case AuthMethodNone:
+		return &noneAuth{}, nil
+	case AuthMethodToken:
+		if len(token) == 0 {
+			return nil, errors.New("github token should be set for token auth")
+		}
+
+		return &tokenAuth{token: token}, nil
+	case AuthMethodApp:
+		a, err := strconv.ParseInt(appID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse github app id: %w", err)
+		}
+
+		i, err := strconv.ParseInt(installationID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse github app installation id: %w", err)
+		}
+
+		p, err := os.ReadFile(privateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read github app private key: %w", err)
+		}
+
+		key, err := parsePrivateKey(p)
+		if err != nil {
+			return nil, err
+		}
+
+		return &appAuth{appID: a, installationID: i, key: key}, nil
+	default:
+		return nil, fmt.Errorf("github auth method '%s' is not supported", method)
+	}
+}
+
+func parsePrivateKey(p []byte) (*rsa.PrivateKey, error) {
+	b, _ := pem.Decode(p)
+	if b == nil {
+		return nil, errors.New("github app private key is not pem encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(b.Bytes); err == nil {
+		return key, nil
+	}
+
+	k, err := x509.ParsePKCS8PrivateKey(b.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse github app private key: %w", err)
+	}
+
+	key, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("github app private key is not an rsa key")
+	}
+
+	return key, nil
+}
+
+func (a *noneAuth) authenticate(c *github.Client) *github.Client {
+	return c
+}
+
+func (a *tokenAuth) authenticate(c *github.Client) *github.Client {
+	return c.WithAuthToken(a.token)
+}
+
+// authenticate returns a client whose requests carry an installation token, which is exchanged through
+// the given client with a token signed by the app.
+func (a *appAuth) authenticate(c *github.Client) *github.Client {
+	hc := *c.Client()
+	hc.Transport = &appTransport{
+		auth: a,
+		apps: c,
+		base: hc.Transport,
+	}
+
+	n := github.NewClient(&hc)
+	n.BaseURL = c.BaseURL
+	n.UploadURL = c.UploadURL
+
+	return n
+}
+
+// jwt returns a token which authenticates as the app itself.
+func (a *appAuth) jwt() (string, error) {
+	now := time.Now()
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	// issued in the past to tolerate clock drift, as github does
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	s := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	h := sha256.Sum256([]byte(s))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, h[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign github app token: %w", err)
+	}
+
+	return s + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+type appTransport struct {
+	auth *appAuth
+	apps *github.Client
+	base http.RoundTripper
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.installationToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return base.RoundTrip(r)
+}
+
+func (t *appTransport) installationToken(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if time.Until(t.expires) > appTokenLeeway {
+		return t.token, nil
+	}
+
+	jwt, err := t.auth.jwt()
+	if err != nil {
+		return "", err
+	}
+
+	token, _, err := t.apps.WithAuthToken(jwt).Apps.CreateInstallationToken(ctx, t.auth.installationID, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create github app installation token: %w", err)
+	}
+
+	t.token = token.GetToken()
+	t.expires = token.GetExpiresAt().Time
+
+	return t.token, nil
+}
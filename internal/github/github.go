@@ -2,29 +2,127 @@ package github
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
 	"strings"
+	"sync"
 
 	"github.com/google/go-github/v58/github"
 )
 
+var ErrNotFound = errors.New("github resource does not found")
+
 type GitHub interface {
 	GetOrganizationName() string
 	ListRepositories(ctx context.Context) ([]github.Repository, error)
+	GetRepository(ctx context.Context, name string) (*github.Repository, error)
 	GetRepositoryContent(ctx context.Context, name, ref, path string) (*strings.Reader, error)
 	GetRepositoryLatestTag(ctx context.Context, name string) (string, error)
+	ListRepositoryReleaseTags(ctx context.Context, name string) ([]string, error)
 }
 
 type gitHub struct {
 	client *github.Client
 	org    string
+
+	// private repositories are only visible to authenticated clients
+	authenticated bool
+
+	mu       sync.Mutex
+	contents map[string]content
+}
+
+type content struct {
+	etag    string
+	content string
 }
 
-func New(org string) GitHub {
+// Options tells how the client reaches github, where the zero value reaches github.com directly or through
+// the proxy of the environment.
+type Options struct {
+	// BaseURL and UploadURL are the urls of a github enterprise server, where the upload url defaults to
+	// the base url.
+	BaseURL   string
+	UploadURL string
+
+	// CAPath is a pem bundle trusted in addition to the system certificates.
+	CAPath   string
+	ProxyURL string
+}
+
+func New(org string, auth Auth, o Options) (GitHub, error) {
+	if auth == nil {
+		return nil, errors.New("github auth should be set")
+	}
+
+	t, err := newTransport(o)
+	if err != nil {
+		return nil, err
+	}
+
+	c := github.NewClient(&http.Client{Transport: t})
+
+	if len(o.BaseURL) > 0 {
+		upload := o.UploadURL
+		if len(upload) == 0 {
+			upload = o.BaseURL
+		}
+
+		c, err = c.WithEnterpriseURLs(o.BaseURL, upload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set github enterprise urls: %w", err)
+		}
+	}
+
+	_, none := auth.(*noneAuth)
+
 	return &gitHub{
-		client: github.NewClient(nil),
-		org:    org,
+		client:        auth.authenticate(c),
+		org:           org,
+		authenticated: !none,
+		contents:      make(map[string]content),
+	}, nil
+}
+
+func newTransport(o Options) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if len(o.CAPath) > 0 {
+		p, err := os.ReadFile(o.CAPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read github ca bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(p) {
+			return nil, errors.New("github ca bundle does not have any pem certificate")
+		}
+
+		t.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	if len(o.ProxyURL) > 0 {
+		u, err := url.Parse(o.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse github proxy url: %w", err)
+		}
+
+		t.Proxy = http.ProxyURL(u)
 	}
+
+	return t, nil
 }
 
 func (g *gitHub) GetOrganizationName() string {
@@ -32,8 +130,13 @@ func (g *gitHub) GetOrganizationName() string {
 }
 
 func (g *gitHub) ListRepositories(ctx context.Context) ([]github.Repository, error) {
+	t := "public"
+	if g.authenticated {
+		t = "all"
+	}
+
 	opt := github.RepositoryListByOrgOptions{
-		Type:        "public",
+		Type:        t,
 		ListOptions: github.ListOptions{PerPage: 1000},
 	}
 
@@ -58,10 +161,46 @@ func (g *gitHub) ListRepositories(ctx context.Context) ([]github.Repository, err
 	return repos, nil
 }
 
+func (g *gitHub) GetRepository(ctx context.Context, name string) (*github.Repository, error) {
+	r, res, err := g.client.Repositories.Get(ctx, g.org, name)
+	if err != nil && res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	return r, nil
+}
+
+// GetRepositoryContent returns the file content at the ref. Contents are cached with their etag, so that
+// unchanged files are answered by github with not modified, which does not count against the rate limit.
 func (g *gitHub) GetRepositoryContent(ctx context.Context, name, ref, path string) (*strings.Reader, error) {
-	f, _, _, err := g.client.Repositories.GetContents(ctx, g.org, name, path, &github.RepositoryContentGetOptions{
-		Ref: ref,
-	})
+	u := fmt.Sprintf("repos/%s/%s/contents/%s?ref=%s", g.org, name, path, url.QueryEscape(ref))
+	key := name + "/" + ref + "/" + path
+
+	req, err := g.client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository file request: %w", err)
+	}
+
+	g.mu.Lock()
+	cached, ok := g.contents[key]
+	g.mu.Unlock()
+
+	if ok {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	var f github.RepositoryContent
+
+	res, err := g.client.Do(ctx, req, &f)
+	if ok && res != nil && res.StatusCode == http.StatusNotModified {
+		return strings.NewReader(cached.content), nil
+	}
+	if err != nil && res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository file: %w", err)
 	}
@@ -71,6 +210,12 @@ func (g *gitHub) GetRepositoryContent(ctx context.Context, name, ref, path strin
 		return nil, fmt.Errorf("failed to get repository file content: %w", err)
 	}
 
+	if etag := res.Header.Get("ETag"); len(etag) > 0 {
+		g.mu.Lock()
+		g.contents[key] = content{etag: etag, content: c}
+		g.mu.Unlock()
+	}
+
 	return strings.NewReader(c), nil
 }
 
@@ -82,3 +227,30 @@ func (g *gitHub) GetRepositoryLatestTag(ctx context.Context, name string) (strin
 
 	return *r.TagName, nil
 }
+
+// ListRepositoryReleaseTags returns the tags of the published releases, drafts are skipped.
+func (g *gitHub) ListRepositoryReleaseTags(ctx context.Context, name string) ([]string, error) {
+	opt := github.ListOptions{PerPage: 100}
+
+	var tags []string
+	for {
+		list, res, err := g.client.Repositories.ListReleases(ctx, g.org, name, &opt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repository releases: %w", err)
+		}
+
+		for _, r := range list {
+			if !r.GetDraft() {
+				tags = append(tags, r.GetTagName())
+			}
+		}
+
+		if res.NextPage == 0 {
+			break
+		}
+
+		opt.Page = res.NextPage
+	}
+
+	return tags, nil
+}
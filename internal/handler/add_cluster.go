@@ -1,49 +1,136 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jetbuild/engine/internal/k8s"
 	"github.com/jetbuild/engine/internal/model"
-	"github.com/jetbuild/engine/internal/vault"
+	"github.com/jetbuild/engine/internal/storage"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
+// addCluster registers the cluster. When a service account is requested, it is only provisioned once the
+// cluster is known to be new, and it is removed again when the cluster cannot be registered.
 func (h *Handler) addCluster(ctx *fiber.Ctx) error {
-	f, err := ctx.FormFile("kubeConfig")
-	if err != nil {
-		return fmt.Errorf("failed to get kube config file: %w", err)
+	var req model.AddClusterRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
 	}
 
-	cluster, err := k8s.NewFromFormFile(f)
-	if err != nil {
-		return fmt.Errorf("failed to create kubernetes client: %w", err)
-	}
+	var cluster k8s.K8S
+	var err error
 
-	admin, err := cluster.HasAdminPrivileges(ctx.Context())
-	if err != nil {
-		return fmt.Errorf("failed to check admin priveleges: %w", err)
-	}
+	switch req.Body.Type {
+	case model.ClusterTypeInCluster:
+		if req.Body.ServiceAccount {
+			return fiber.NewError(fiber.StatusBadRequest, "service account option requires a kube config")
+		}
+
+		cluster, err = k8s.NewInCluster(req.Body.Name)
+		if err != nil {
+			return fmt.Errorf("failed to create kubernetes client: %w", err)
+		}
+	default:
+		f, fErr := ctx.FormFile("kubeConfig")
+		if fErr != nil {
+			return fmt.Errorf("failed to get kube config file: %w", fErr)
+		}
 
-	if !admin {
-		return fiber.NewError(fiber.StatusUnauthorized, "kube config does not have admin privileges")
+		cluster, err = k8s.NewFromFormFile(f, req.Body.Context)
+		if err != nil {
+			return fmt.Errorf("failed to create kubernetes client: %w", err)
+		}
 	}
 
 	name := cluster.GetClusterName()
 
-	err = h.ClusterRepository.Add(ctx.Context(), name, model.Cluster{
-		Name:   name,
-		Config: cluster.GetConfig(),
-	})
-	if err != nil && errors.Is(err, vault.ErrItemAlreadyExist) {
+	_, _, err = h.ClusterRepository.Get(ctx.Context(), name)
+	if err == nil {
 		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("cluster '%s' already exist", name))
 	}
+	if !errors.Is(err, storage.ErrKeyNotFound) {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	var registered bool
+
+	if req.Body.ServiceAccount {
+		var cleanup func(context.Context) error
+
+		cluster, cleanup, err = cluster.CreateServiceAccount(ctx.Context(), req.Body.Namespaces)
+		if apierrors.IsForbidden(err) {
+			return fiber.NewError(fiber.StatusForbidden, "kube config is not allowed to create the service account")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create service account: %w", err)
+		}
+
+		defer func() {
+			if registered {
+				return
+			}
+
+			if cErr := cleanup(ctx.Context()); cErr != nil {
+				slog.Warn("failed to remove unused service account", "cluster", name, "error", cErr)
+			}
+		}()
+	}
+
+	res, registered, err := h.registerCluster(ctx, cluster, name, req.Body.Type, req.Body.Namespaces)
+	if !registered {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(res)
+}
+
+// registerCluster checks the permissions of the client before saving its cluster. Like checkPermissions,
+// it reports whether the cluster is registered, as the forbidden response is written already otherwise.
+func (h *Handler) registerCluster(ctx *fiber.Ctx, c k8s.K8S, name, clusterType string, namespaces []string) (*model.CheckClusterPermissionsResponse, bool, error) {
+	res, ok, err := h.checkPermissions(ctx, c, name, namespaces)
+	if !ok {
+		return nil, false, err
+	}
+
+	err = h.saveCluster(ctx.Context(), c, clusterType, namespaces)
+	if err != nil && errors.Is(err, storage.ErrItemAlreadyExist) {
+		return nil, false, fiber.NewError(fiber.StatusConflict, fmt.Sprintf("cluster '%s' already exist", name))
+	}
 	if err != nil {
-		return fmt.Errorf("failed to save cluster to vault: %w", err)
+		return nil, false, err
 	}
 
-	ctx.Status(fiber.StatusCreated)
+	return res, true, nil
+}
+
+// saveCluster adds the cluster of the client to the storage, with its kube config sealed.
+func (h *Handler) saveCluster(ctx context.Context, c k8s.K8S, clusterType string, namespaces []string) error {
+	cluster := model.Cluster{
+		Name:       c.GetClusterName(),
+		Type:       clusterType,
+		Namespaces: namespaces,
+		AuthMethod: c.GetAuthMethod(),
+		Inventory:  inventory(ctx, c, nil),
+	}
+
+	if !cluster.IsInCluster() {
+		cluster.Type = model.ClusterTypeKubeConfig
+
+		cfg, err := h.sealConfig(ctx, c.GetConfig())
+		if err != nil {
+			return err
+		}
+
+		cluster.Config = cfg
+	}
+
+	if err := h.ClusterRepository.Add(ctx, cluster.Name, cluster); err != nil {
+		return fmt.Errorf("failed to save cluster to storage: %w", err)
+	}
 
 	return nil
 }
@@ -5,9 +5,8 @@ import (
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/jetbuild/engine/internal/k8s"
 	"github.com/jetbuild/engine/internal/model"
-	"github.com/jetbuild/engine/internal/vault"
+	"github.com/jetbuild/engine/internal/storage"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
@@ -17,15 +16,19 @@ func (h *Handler) addClusterNamespace(ctx *fiber.Ctx) error {
 		return err
 	}
 
-	cluster, err := h.ClusterRepository.Get(ctx.Context(), req.Params.ClusterName)
-	if err != nil && errors.Is(err, vault.ErrKeyNotFound) {
-		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in vault")
+	cluster, _, err := h.ClusterRepository.Get(ctx.Context(), req.Params.ClusterName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in storage")
 	}
 	if err != nil {
-		return fmt.Errorf("failed to get cluster from vault: %w", err)
+		return fmt.Errorf("failed to get cluster from storage: %w", err)
 	}
 
-	c, err := k8s.New(cluster.Config)
+	if len(cluster.Namespaces) > 0 {
+		return fiber.NewError(fiber.StatusForbidden, "cluster credentials are scoped to namespaces, which cannot create namespaces")
+	}
+
+	c, err := h.newK8S(ctx.Context(), cluster)
 	if err != nil {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
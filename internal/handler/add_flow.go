@@ -6,37 +6,21 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jetbuild/engine/internal/model"
-	"github.com/jetbuild/engine/internal/vault"
-	"github.com/jetbuild/engine/pkg/flow"
+	"github.com/jetbuild/engine/internal/storage"
 )
 
 func (h *Handler) addFlow(ctx *fiber.Ctx) error {
 	var req model.AddFlowRequest
-	if err := req.Bind(ctx, h.Validator, h.Components); err != nil {
+	if err := req.Bind(ctx, h.Validator, h.Catalog.Components()); err != nil {
 		return err
 	}
 
-	f := flow.Flow{
-		Name: req.Name,
-	}
-
-	for _, c := range req.Components {
-		f.Components = append(f.Components, flow.Component{
-			Key:       c.Key,
-			Version:   c.Version,
-			Arguments: c.Arguments,
-			Connections: &flow.ComponentConnection{
-				Targets: c.Connections.Targets,
-			},
-		})
-	}
-
-	err := h.FlowRepository.Add(ctx.Context(), req.Name, f)
-	if err != nil && errors.Is(err, vault.ErrItemAlreadyExist) {
+	err := h.FlowRepository.Add(h.authorContext(ctx), req.Name, newFlow(req))
+	if err != nil && errors.Is(err, storage.ErrItemAlreadyExist) {
 		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("flow '%s' already exist", req.Name))
 	}
 	if err != nil {
-		return fmt.Errorf("failed to save flow to vault: %w", err)
+		return fmt.Errorf("failed to save flow to storage: %w", err)
 	}
 
 	ctx.Status(fiber.StatusCreated)
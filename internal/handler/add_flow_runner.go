@@ -6,9 +6,8 @@ import (
 	"slices"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/jetbuild/engine/internal/k8s"
 	"github.com/jetbuild/engine/internal/model"
-	"github.com/jetbuild/engine/internal/vault"
+	"github.com/jetbuild/engine/internal/storage"
 	"github.com/jetbuild/engine/pkg/flow"
 	v1 "k8s.io/api/core/v1"
 )
@@ -19,12 +18,16 @@ func (h *Handler) addFlowRunner(ctx *fiber.Ctx) error {
 		return err
 	}
 
-	f, err := h.FlowRepository.Get(ctx.Context(), req.Params.FlowName)
-	if err != nil && errors.Is(err, vault.ErrKeyNotFound) {
-		return fiber.NewError(fiber.StatusNotFound, "flow does not found in vault")
+	if err := checkReplicas(req.Body.MinReplicas, req.Body.MaxReplicas); err != nil {
+		return err
+	}
+
+	f, version, err := h.FlowRepository.Get(ctx.Context(), req.Params.FlowName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage")
 	}
 	if err != nil {
-		return fmt.Errorf("failed to get flow from vault: %w", err)
+		return fmt.Errorf("failed to get flow from storage: %w", err)
 	}
 
 	if slices.ContainsFunc(f.Runners, func(r flow.Runner) bool {
@@ -33,57 +36,72 @@ func (h *Handler) addFlowRunner(ctx *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusConflict, "runner already exist for flow")
 	}
 
-	cluster, err := h.ClusterRepository.Get(ctx.Context(), req.Body.Cluster)
-	if err != nil && errors.Is(err, vault.ErrKeyNotFound) {
-		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in vault")
+	cluster, _, err := h.ClusterRepository.Get(ctx.Context(), req.Body.Cluster)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in storage")
 	}
 	if err != nil {
 		return fmt.Errorf("failed to get cluster: %w", err)
 	}
 
-	c, err := k8s.New(cluster.Config)
-	if err != nil {
-		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	if !cluster.AllowsNamespace(req.Body.Namespace) {
+		return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("namespace is not allowed in cluster '%s'", req.Body.Cluster))
 	}
 
-	namespaces, err := c.ListNamespaces(ctx.Context())
+	c, err := h.newK8S(ctx.Context(), cluster)
 	if err != nil {
-		return fmt.Errorf("failed to list cluster namespaces: %w", err)
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
-	if !slices.ContainsFunc(namespaces.Items, func(n v1.Namespace) bool {
-		if n.Name == req.Body.Namespace {
-			return true
+	// namespace scoped credentials cannot list the namespaces, the allow-list is trusted instead
+	if len(cluster.Namespaces) == 0 {
+		namespaces, err := c.ListNamespaces(ctx.Context())
+		if err != nil {
+			return fmt.Errorf("failed to list cluster namespaces: %w", err)
 		}
 
-		return false
-	}) {
-		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("namespace does not exist in cluster '%s'", req.Body.Cluster))
-	}
+		if !slices.ContainsFunc(namespaces.Items, func(n v1.Namespace) bool {
+			if n.Name == req.Body.Namespace {
+				return true
+			}
 
-	// TODO: create necessary k8s resources
-	/*
-		if err = c.CreateDeployment(ctx.Context(), req.Body.Namespace); err != nil {
-			return fmt.Errorf("failed to create deployment: %w", err)
+			return false
+		}) {
+			return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("namespace does not exist in cluster '%s'", req.Body.Cluster))
 		}
+	}
 
-		if err = c.CreateHPA(ctx.Context(), req.Body.Namespace); err != nil {
-			return fmt.Errorf("failed to create hpa: %w", err)
-		}
-	*/
+	runner := flow.Runner{
+		Cluster:     req.Body.Cluster,
+		Namespace:   req.Body.Namespace,
+		Version:     h.LatestRunnerVersion,
+		MinReplicas: req.Body.MinReplicas,
+		MaxReplicas: req.Body.MaxReplicas,
+	}
+
+	r, err := h.newRunner(*f, runner)
+	if err != nil {
+		return err
+	}
 
-	f.Runners = append(f.Runners, flow.Runner{
-		Cluster:   req.Body.Cluster,
-		Namespace: req.Body.Namespace,
-		Version:   h.LatestRunnerVersion,
-	})
+	if err = provisionRunner(ctx.Context(), c, r); err != nil {
+		return fmt.Errorf("failed to provision runner: %w", err)
+	}
+
+	f.Runners = append(f.Runners, runner)
 
-	err = h.FlowRepository.Update(ctx.Context(), req.Params.FlowName, *f)
-	if err != nil && errors.Is(err, vault.ErrKeyNotFound) {
-		return fiber.NewError(fiber.StatusNotFound, "flow does not found in vault for update")
+	err = h.FlowRepository.Update(h.authorContext(ctx), req.Params.FlowName, *f, version)
+	if err != nil {
+		err = errors.Join(err, deprovisionRunner(ctx.Context(), c, r))
+	}
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage for update")
+	}
+	if err != nil && errors.Is(err, storage.ErrConflict) {
+		return fiber.NewError(fiber.StatusConflict, "flow was modified concurrently, retry the request")
 	}
 	if err != nil {
-		return fmt.Errorf("failed to update flow from vault: %w", err)
+		return fmt.Errorf("failed to update flow from storage: %w", err)
 	}
 
 	ctx.Status(fiber.StatusCreated)
@@ -8,7 +8,7 @@ import (
 
 func (h *Handler) checkHealth(ctx *fiber.Ctx) error {
 	if err := h.ClusterRepository.Ping(ctx.Context()); err != nil {
-		return fmt.Errorf("failed to ping vault: %w", err)
+		return fmt.Errorf("failed to ping storage: %w", err)
 	}
 
 	ctx.Status(fiber.StatusNoContent)
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+	"github.com/jetbuild/engine/pkg/flow"
+)
+
+func newFlow(req model.AddFlowRequest) flow.Flow {
+	f := flow.Flow{
+		Name: req.Name,
+	}
+
+	for _, c := range req.Components {
+		f.Components = append(f.Components, flow.Component{
+			Key:       c.Key,
+			Version:   c.Version,
+			Arguments: c.Arguments,
+			Connections: &flow.ComponentConnection{
+				Targets: c.Connections.Targets,
+			},
+		})
+	}
+
+	return f
+}
+
+func newFlowRequest(f flow.Flow) model.AddFlowRequest {
+	req := model.AddFlowRequest{
+		Name: f.Name,
+	}
+
+	for _, c := range f.Components {
+		rc := model.AddFlowRequestComponent{
+			Key:       c.Key,
+			Version:   c.Version,
+			Arguments: c.Arguments,
+		}
+
+		if c.Connections != nil {
+			rc.Connections.Targets = c.Connections.Targets
+		}
+
+		req.Components = append(req.Components, rc)
+	}
+
+	return req
+}
+
+// authorContext returns the request context, whose flow writes are recorded as made by the requesting user.
+// The user is only taken from the configured author header of requests sent by a trusted proxy, as any
+// other caller could set it to anyone. Otherwise, no author is recorded.
+func (h *Handler) authorContext(ctx *fiber.Ctx) context.Context {
+	if len(h.Config.ServerAuthorHeader) == 0 || !ctx.IsProxyTrusted() {
+		return ctx.Context()
+	}
+
+	return storage.WithAuthor(ctx.Context(), ctx.Get(h.Config.ServerAuthorHeader))
+}
+
+// ifMatchVersion returns the item version of the If-Match header, which is the ETag of a previous read.
+func ifMatchVersion(ctx *fiber.Ctx) (int64, bool, error) {
+	h := ctx.Get(fiber.HeaderIfMatch)
+	if len(h) == 0 {
+		return 0, false, nil
+	}
+
+	v, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(h, "W/"), `"`), 10, 64)
+	if err != nil {
+		return 0, false, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("if-match header '%s' is invalid", h))
+	}
+
+	return v, true, nil
+}
+
+func setETag(ctx *fiber.Ctx, version int64) {
+	ctx.Set(fiber.HeaderETag, fmt.Sprintf(`"%d"`, version))
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+func (h *Handler) getCluster(ctx *fiber.Ctx) error {
+	var req model.GetClusterRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	cluster, _, err := h.ClusterRepository.Get(ctx.Context(), req.Params.ClusterName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	return ctx.JSON(model.Cluster{
+		Name:       cluster.Name,
+		Type:       cluster.Type,
+		Namespaces: cluster.Namespaces,
+		AuthMethod: cluster.AuthMethod,
+		Inventory:  cluster.Inventory,
+	})
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+func (h *Handler) getFlow(ctx *fiber.Ctx) error {
+	var req model.GetFlowRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	f, version, err := h.FlowRepository.Get(ctx.Context(), req.Params.FlowName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get flow from storage: %w", err)
+	}
+
+	setETag(ctx, version)
+
+	return ctx.JSON(h.newFlowResponse(ctx.Context(), *f, true))
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+)
+
+func (h *Handler) getFlowRunner(ctx *fiber.Ctx) error {
+	var req model.GetFlowRunnerRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	fr, err := h.findFlowRunner(ctx.Context(), req.Params.FlowName, req.Params.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(model.FlowRunner{
+		Runner: fr.runner(),
+		Status: h.runnerStatus(ctx.Context(), *fr.flow, fr.runner()),
+	})
+}
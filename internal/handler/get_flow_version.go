@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+func (h *Handler) getFlowVersion(ctx *fiber.Ctx) error {
+	var req model.GetFlowVersionRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	f, err := h.FlowRepository.GetVersion(ctx.Context(), req.Params.FlowName, req.Params.Version)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("flow version '%d' does not found in storage", req.Params.Version))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get flow version from storage: %w", err)
+	}
+
+	return ctx.JSON(f)
+}
@@ -1,38 +1,61 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/jetbuild/engine/internal/catalog"
 	"github.com/jetbuild/engine/internal/config"
 	"github.com/jetbuild/engine/internal/github"
+	"github.com/jetbuild/engine/internal/k8s"
 	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
 	"github.com/jetbuild/engine/internal/vault"
 	"github.com/jetbuild/engine/pkg/flow"
-	"github.com/valyala/fasthttp"
 )
 
 type Handler struct {
 	Validator           *validator.Validate
-	ClusterRepository   vault.Vault[model.Cluster]
-	FlowRepository      vault.Vault[flow.Flow]
+	ClusterRepository   storage.Storage[model.Cluster]
+	FlowRepository      storage.Storage[flow.Flow]
+	Transit             *vault.Transit
+	Clients             *k8s.Pool
 	Config              *config.Config
-	Components          []model.Component
+	Catalog             *catalog.Catalog
 	GitHub              github.GitHub
 	LatestRunnerVersion string
+
+	// StatusConcurrency bounds the runner statuses which are queried at once by a request.
+	StatusConcurrency int
 }
 
 func (h *Handler) Start() error {
+	var proxies []string
+	for _, p := range strings.Split(h.Config.ServerTrustedProxies, ",") {
+		if p = strings.TrimSpace(p); len(p) > 0 {
+			proxies = append(proxies, p)
+		}
+	}
+
+	// without trusted proxies every request would be trusted, so the author header would be forgeable
+	if len(h.Config.ServerAuthorHeader) > 0 && len(proxies) == 0 {
+		return errors.New("server author header requires server trusted proxies")
+	}
+
 	f := fiber.New(fiber.Config{
-		DisableStartupMessage: true,
-		ErrorHandler:          errorHandler,
+		DisableStartupMessage:   true,
+		ErrorHandler:            errorHandler,
+		EnableTrustedProxyCheck: len(proxies) > 0,
+		TrustedProxies:          proxies,
 	})
 
 	f.Use(recover.New(recover.Config{
@@ -42,12 +65,32 @@ func (h *Handler) Start() error {
 		Get("/readyz", h.checkHealth).
 		Get("/clusters", h.listClusters).
 		Post("/clusters", h.addCluster).
+		Post("/clusters/rewrap", h.rewrapClusters).
+		Get("/clusters/:name", h.getCluster).
+		Delete("/clusters/:name", h.removeCluster).
+		Put("/clusters/:name/kubeconfig", h.updateClusterKubeConfig).
+		Post("/clusters/:name/refresh", h.refreshCluster).
 		Get("/clusters/:name/namespaces", h.listClusterNamespaces).
 		Post("/clusters/:name/namespaces", h.addClusterNamespace).
 		Get("/components", h.listComponents).
+		Get("/components/:key/versions", h.listComponentVersions).
+		Post("/webhooks/github", h.receiveGitHubWebhook).
 		Get("/flows", h.listFlows).
 		Post("/flows", h.addFlow).
-		Post("/flows/:name/runners", h.addFlowRunner)
+		Get("/flows/:name", h.getFlow).
+		Put("/flows/:name", h.updateFlow).
+		Patch("/flows/:name", h.patchFlow).
+		Delete("/flows/:name", h.removeFlow).
+		Get("/flows/:name/versions", h.listFlowVersions).
+		Get("/flows/:name/versions/:version", h.getFlowVersion).
+		Post("/flows/:name/rollback", h.rollbackFlow).
+		Get("/flows/:name/runners", h.listFlowRunners).
+		Post("/flows/:name/runners", h.addFlowRunner).
+		Get("/flows/:name/runners/:cluster", h.getFlowRunner).
+		Patch("/flows/:name/runners/:cluster", h.updateFlowRunner).
+		Delete("/flows/:name/runners/:cluster", h.removeFlowRunner).
+		Get("/flows/:name/runners/:cluster/logs", h.streamFlowRunnerLogs).
+		Get("/flows/:name/runners/:cluster/events", h.listFlowRunnerEvents)
 
 	f.Hooks().OnListen(func(d fiber.ListenData) error {
 		if fiber.IsChild() {
@@ -74,13 +117,30 @@ func (h *Handler) Start() error {
 		return nil
 	})
 
-	ctx := f.AcquireCtx(&fasthttp.RequestCtx{})
-	ctx.Locals("loadComponents", true)
+	rctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	if err := h.listComponents(ctx); err != nil {
+	if err := h.Catalog.Load(rctx); err != nil {
 		return fmt.Errorf("failed to load components: %w", err)
 	}
 
+	interval, err := config.PositiveDuration(h.Config.ReconcileInterval)
+	if err != nil {
+		return fmt.Errorf("failed to parse reconcile interval duration: %w", err)
+	}
+
+	refresh, err := config.PositiveDuration(h.Config.CatalogRefreshInterval)
+	if err != nil {
+		return fmt.Errorf("failed to parse catalog refresh interval duration: %w", err)
+	}
+
+	go h.reconcile(rctx, interval)
+	go h.Catalog.Run(rctx, refresh)
+
+	if h.Clients != nil {
+		go h.Clients.Run(rctx)
+	}
+
 	go func() {
 		if err := f.Listen(h.Config.ServerAddr); err != nil {
 			slog.Error("failed to start server", "error", err)
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/k8s"
+	"github.com/jetbuild/engine/internal/model"
+)
+
+// newK8S returns the client of the cluster, which is reused from the pool while the stored cluster is
+// unchanged.
+func (h *Handler) newK8S(ctx context.Context, cluster *model.Cluster) (k8s.K8S, error) {
+	if h.Clients == nil {
+		return h.createK8S(ctx, cluster)
+	}
+
+	// only what the client is created from is hashed, so that metadata updates keep the client
+	p, err := json.Marshal(model.Cluster{
+		Type:   cluster.Type,
+		Config: cluster.Config,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cluster: %w", err)
+	}
+
+	hash := sha256.Sum256(p)
+
+	return h.Clients.Get(cluster.Name, hex.EncodeToString(hash[:]), func() (k8s.K8S, error) {
+		return h.createK8S(ctx, cluster)
+	})
+}
+
+func (h *Handler) createK8S(ctx context.Context, cluster *model.Cluster) (k8s.K8S, error) {
+	if cluster.IsInCluster() {
+		return k8s.NewInCluster(cluster.Name)
+	}
+
+	var d k8s.Decrypter
+	if h.Transit != nil {
+		d = h.Transit
+	}
+
+	return k8s.New(ctx, cluster.Config, d)
+}
+
+// evictK8S drops the pooled client of the cluster after it is updated or removed.
+func (h *Handler) evictK8S(name string) {
+	if h.Clients != nil {
+		h.Clients.Evict(name)
+	}
+}
+
+// inventory contacts the cluster for its inventory. An unreachable cluster keeps the last known inventory,
+// flagged as unreachable.
+func inventory(ctx context.Context, c k8s.K8S, last *model.ClusterInventory) *model.ClusterInventory {
+	i, err := c.GetInventory(ctx)
+	if err == nil {
+		return i
+	}
+
+	slog.Warn("failed to get cluster inventory", "cluster", c.GetClusterName(), "error", err)
+
+	if last == nil {
+		last = &model.ClusterInventory{}
+	}
+
+	u := *last
+	u.Reachable = false
+
+	return &u
+}
+
+// sealConfig returns the kube config in the form it is stored, which is encrypted when transit is enabled.
+func (h *Handler) sealConfig(ctx context.Context, cfg any) (any, error) {
+	if h.Transit == nil {
+		return cfg, nil
+	}
+
+	p, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal kube config: %w", err)
+	}
+
+	c, err := h.Transit.Encrypt(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt kube config: %w", err)
+	}
+
+	return c, nil
+}
+
+// checkPermissions reviews the permissions of the kube config, and writes the report as the forbidden
+// response when any of the required ones is denied.
+func (h *Handler) checkPermissions(ctx *fiber.Ctx, c k8s.K8S, name string, namespaces []string) (*model.CheckClusterPermissionsResponse, bool, error) {
+	permissions, err := c.CheckPermissions(ctx.Context(), namespaces)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	res := model.CheckClusterPermissionsResponse{
+		Name:        name,
+		AuthMethod:  c.GetAuthMethod(),
+		Namespaces:  namespaces,
+		Permissions: permissions,
+	}
+
+	if slices.ContainsFunc(permissions, func(p model.Permission) bool {
+		return !p.Allowed && !p.Optional
+	}) {
+		return nil, false, ctx.Status(fiber.StatusForbidden).JSON(res)
+	}
+
+	return &res, true, nil
+}
@@ -5,9 +5,8 @@ import (
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/jetbuild/engine/internal/k8s"
 	"github.com/jetbuild/engine/internal/model"
-	"github.com/jetbuild/engine/internal/vault"
+	"github.com/jetbuild/engine/internal/storage"
 )
 
 func (h *Handler) listClusterNamespaces(ctx *fiber.Ctx) error {
@@ -20,15 +19,26 @@ func (h *Handler) listClusterNamespaces(ctx *fiber.Ctx) error {
 		Items: make([]model.ClusterNamespace, 0),
 	}
 
-	cluster, err := h.ClusterRepository.Get(ctx.Context(), req.Params.ClusterName)
-	if err != nil && errors.Is(err, vault.ErrKeyNotFound) {
-		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in vault")
+	cluster, _, err := h.ClusterRepository.Get(ctx.Context(), req.Params.ClusterName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in storage")
 	}
 	if err != nil {
 		return fmt.Errorf("failed to get cluster: %w", err)
 	}
 
-	c, err := k8s.New(cluster.Config)
+	// namespace scoped credentials cannot list the namespaces, so the allow-list is returned instead
+	if len(cluster.Namespaces) > 0 {
+		for _, namespace := range cluster.Namespaces {
+			res.Items = append(res.Items, model.ClusterNamespace{
+				Name: namespace,
+			})
+		}
+
+		return ctx.JSON(res)
+	}
+
+	c, err := h.newK8S(ctx.Context(), cluster)
 	if err != nil {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
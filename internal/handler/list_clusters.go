@@ -6,7 +6,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jetbuild/engine/internal/model"
-	"github.com/jetbuild/engine/internal/vault"
+	"github.com/jetbuild/engine/internal/storage"
 )
 
 func (h *Handler) listClusters(ctx *fiber.Ctx) error {
@@ -15,7 +15,7 @@ func (h *Handler) listClusters(ctx *fiber.Ctx) error {
 	}
 
 	clusters, err := h.ClusterRepository.List(ctx.Context())
-	if err != nil && errors.Is(err, vault.ErrKeyNotFound) {
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
 		return ctx.Status(fiber.StatusNotFound).JSON(res)
 	}
 	if err != nil {
@@ -24,7 +24,11 @@ func (h *Handler) listClusters(ctx *fiber.Ctx) error {
 
 	for _, cluster := range clusters {
 		res.Items = append(res.Items, model.Cluster{
-			Name: cluster.Name,
+			Name:       cluster.Name,
+			Type:       cluster.Type,
+			Namespaces: cluster.Namespaces,
+			AuthMethod: cluster.AuthMethod,
+			Inventory:  cluster.Inventory,
 		})
 	}
 
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+)
+
+func (h *Handler) listComponentVersions(ctx *fiber.Ctx) error {
+	var req model.ListComponentVersionsRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	versions := h.Catalog.Versions(req.Params.Key)
+	if len(versions) == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "component does not found in catalog")
+	}
+
+	return ctx.JSON(model.ListComponentVersionsResponse{
+		Items: versions,
+	})
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+)
+
+const eventsHeartbeatInterval = 15 * time.Second
+
+// listFlowRunnerEvents returns the kubernetes events of the runner resources. When watched, the events
+// are streamed as server-sent events until the client goes away.
+func (h *Handler) listFlowRunnerEvents(ctx *fiber.Ctx) error {
+	var req model.ListFlowRunnerEventsRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	fr, err := h.findFlowRunner(ctx.Context(), req.Params.FlowName, req.Params.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	c, err := h.newK8S(ctx.Context(), fr.cluster)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	r, err := h.newRunner(*fr.flow, fr.runner())
+	if err != nil {
+		return err
+	}
+
+	if !req.Query.Watch {
+		events, lErr := c.ListRunnerEvents(ctx.Context(), r)
+		if lErr != nil {
+			return fmt.Errorf("failed to list runner events: %w", lErr)
+		}
+
+		return ctx.JSON(model.ListRunnerEventsResponse{
+			Items: events,
+		})
+	}
+
+	// the watch outlives the handler, it is cancelled once the client goes away
+	wctx, cancel := context.WithCancel(context.Background())
+
+	events, err := c.WatchRunnerEvents(wctx, r)
+	if err != nil {
+		cancel()
+
+		return fmt.Errorf("failed to watch runner events: %w", err)
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		// heartbeats detect clients which went away while no event happens
+		t := time.NewTicker(eventsHeartbeatInterval)
+		defer t.Stop()
+
+		for {
+			var wErr error
+
+			select {
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+
+				b, mErr := json.Marshal(e)
+				if mErr != nil {
+					return
+				}
+
+				_, wErr = fmt.Fprintf(w, "data: %s\n\n", b)
+			case <-t.C:
+				_, wErr = fmt.Fprint(w, ": heartbeat\n\n")
+			}
+
+			if wErr == nil {
+				wErr = w.Flush()
+			}
+			if wErr != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+func (h *Handler) listFlowRunners(ctx *fiber.Ctx) error {
+	var req model.ListFlowRunnersRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	res := model.ListFlowRunnersResponse{
+		Items: make([]model.FlowRunner, 0),
+	}
+
+	f, _, err := h.FlowRepository.Get(ctx.Context(), req.Params.FlowName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get flow from storage: %w", err)
+	}
+
+	res.Items = append(res.Items, h.newFlowResponse(ctx.Context(), *f, true).Runners...)
+
+	return ctx.JSON(res)
+}
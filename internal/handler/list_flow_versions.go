@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+func (h *Handler) listFlowVersions(ctx *fiber.Ctx) error {
+	var req model.ListFlowVersionsRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	res := model.ListFlowVersionsResponse{
+		Items: make([]model.FlowVersion, 0),
+	}
+
+	versions, err := h.FlowRepository.ListVersions(ctx.Context(), req.Params.FlowName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list flow versions from storage: %w", err)
+	}
+
+	for _, v := range versions {
+		res.Items = append(res.Items, model.FlowVersion{
+			Version:   v.Version,
+			Created:   v.Created,
+			Author:    v.Author,
+			Deleted:   v.Deleted,
+			Destroyed: v.Destroyed,
+		})
+	}
+
+	return ctx.JSON(res)
+}
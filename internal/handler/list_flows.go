@@ -6,26 +6,34 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jetbuild/engine/internal/model"
-	"github.com/jetbuild/engine/internal/vault"
+	"github.com/jetbuild/engine/internal/storage"
 	"github.com/jetbuild/engine/pkg/flow"
 )
 
 func (h *Handler) listFlows(ctx *fiber.Ctx) error {
+	var req model.ListFlowsRequest
+	if err := req.Bind(ctx); err != nil {
+		return err
+	}
+
 	res := model.ListFlowsResponse{
-		Items: make([]flow.Flow, 0),
+		Items: make([]model.Flow, 0),
 	}
 
 	flows, err := h.FlowRepository.List(ctx.Context())
-	if err != nil && errors.Is(err, vault.ErrKeyNotFound) {
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
 		return ctx.Status(fiber.StatusNotFound).JSON(res)
 	}
 	if err != nil {
 		return fmt.Errorf("failed to list flows: %w", err)
 	}
 
+	items := make([]flow.Flow, 0, len(flows))
 	for _, f := range flows {
-		res.Items = append(res.Items, f)
+		items = append(items, f)
 	}
 
+	res.Items = h.newFlowResponses(ctx.Context(), items, req.Query.Status)
+
 	return ctx.JSON(res)
 }
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// mergePatch applies a JSON merge patch, as described in RFC 7386, to the target document.
+func mergePatch(target, patch []byte) ([]byte, error) {
+	var t, p any
+
+	if err := json.Unmarshal(target, &t); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal merge patch target: %w", err)
+	}
+
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal merge patch: %w", err)
+	}
+
+	return json.Marshal(mergeValue(t, p))
+}
+
+func mergeValue(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+
+			continue
+		}
+
+		t[k] = mergeValue(t[k], v)
+	}
+
+	return t
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+)
+
+func (h *Handler) patchFlow(ctx *fiber.Ctx) error {
+	var req model.PatchFlowRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	return h.replaceFlow(ctx, req.Params.FlowName, func(current model.AddFlowRequest) (*model.AddFlowRequest, error) {
+		c, err := json.Marshal(current)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal flow: %w", err)
+		}
+
+		p, err := mergePatch(c, ctx.Body())
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+
+		var patched model.AddFlowRequest
+		if err = json.Unmarshal(p, &patched); err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to unmarshal patched flow: %s", err))
+		}
+
+		if patched.Name != current.Name {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "flow name cannot be changed")
+		}
+
+		if err = h.Validator.Struct(patched); err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+
+		if err = patched.Validate(h.Catalog.Components()); err != nil {
+			return nil, err
+		}
+
+		return &patched, nil
+	})
+}
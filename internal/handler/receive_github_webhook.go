@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-github/v58/github"
+	"github.com/jetbuild/engine/internal/catalog"
+)
+
+// receiveGitHubWebhook reloads the component repository of push, release and repository events, after
+// verifying they are signed with the webhook secret.
+func (h *Handler) receiveGitHubWebhook(ctx *fiber.Ctx) error {
+	if len(h.Config.GithubWebhookSecret) == 0 {
+		return fiber.NewError(fiber.StatusNotImplemented, "github webhook secret is not configured")
+	}
+
+	err := github.ValidateSignature(ctx.Get(github.SHA256SignatureHeader), ctx.Body(), []byte(h.Config.GithubWebhookSecret))
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "github webhook signature does not valid")
+	}
+
+	t := ctx.Get(github.EventTypeHeader)
+
+	var repo string
+
+	switch t {
+	case "push", "release", "repository":
+		e, pErr := github.ParseWebHook(t, ctx.Body())
+		if pErr != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to parse github webhook payload: %s", pErr))
+		}
+
+		switch e := e.(type) {
+		case *github.PushEvent:
+			repo = e.GetRepo().GetName()
+		case *github.ReleaseEvent:
+			repo = e.GetRepo().GetName()
+		case *github.RepositoryEvent:
+			repo = e.GetRepo().GetName()
+		}
+	}
+
+	if !catalog.IsComponentRepository(repo) {
+		ctx.Status(fiber.StatusNoContent)
+
+		return nil
+	}
+
+	if err = h.Catalog.Reload(ctx.Context(), repo); err != nil {
+		return fmt.Errorf("failed to reload component repository '%s': %w", repo, err)
+	}
+
+	slog.Info("component repository reloaded", slog.String("repository", repo), slog.String("event", t))
+
+	ctx.Status(fiber.StatusNoContent)
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jetbuild/engine/internal/k8s"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+type reconcileSummary struct {
+	clusters int
+	runners  int
+	changes  []string
+	errors   []error
+}
+
+func (s *reconcileSummary) fail(err error) {
+	s.errors = append(s.errors, err)
+}
+
+// reconcile keeps the runner workloads of every cluster in sync with the stored flows until the
+// context is done.
+func (h *Handler) reconcile(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		h.reconcileOnce(ctx, interval)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (h *Handler) reconcileOnce(ctx context.Context, interval time.Duration) {
+	start := time.Now()
+
+	clusters, err := h.ClusterRepository.List(ctx)
+	if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+		slog.Error("failed to list clusters for reconciliation", "error", err)
+
+		return
+	}
+
+	flows, err := h.FlowRepository.List(ctx)
+	if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+		slog.Error("failed to list flows for reconciliation", "error", err)
+
+		return
+	}
+
+	var s reconcileSummary
+
+	// known holds every runner found in storage, even the ones which cannot be built, so that their
+	// workloads are never deleted as orphans
+	known := make(map[string]bool)
+	desired := make(map[string][]k8s.Runner)
+	for _, f := range flows {
+		for _, r := range f.Runners {
+			known[runnerKey(r.Cluster, f.Name, r.Namespace)] = true
+
+			cluster, ok := clusters[r.Cluster]
+			if !ok {
+				s.fail(fmt.Errorf("cluster '%s' of flow '%s' runner does not found", r.Cluster, f.Name))
+
+				continue
+			}
+
+			if !cluster.AllowsNamespace(r.Namespace) {
+				s.fail(fmt.Errorf("namespace '%s' of flow '%s' runner is not allowed in cluster '%s'", r.Namespace, f.Name, r.Cluster))
+
+				continue
+			}
+
+			kr, rErr := h.newRunner(f, r)
+			if rErr != nil {
+				s.fail(fmt.Errorf("flow '%s': %w", f.Name, rErr))
+
+				continue
+			}
+
+			desired[r.Cluster] = append(desired[r.Cluster], kr)
+		}
+	}
+
+	for name, cluster := range clusters {
+		s.clusters++
+
+		c, cErr := h.newK8S(ctx, &cluster)
+		if cErr != nil {
+			s.fail(fmt.Errorf("cluster '%s': failed to create kubernetes client: %w", name, cErr))
+
+			continue
+		}
+
+		actual, lErr := c.ListRunners(ctx, cluster.Namespaces)
+		if lErr != nil {
+			s.fail(fmt.Errorf("cluster '%s': %w", name, lErr))
+
+			continue
+		}
+
+		for _, r := range desired[name] {
+			s.runners++
+
+			changes, sErr := c.SyncRunner(ctx, r)
+			for _, change := range changes {
+				s.changes = append(s.changes, fmt.Sprintf("%s/%s: %s", name, r.Namespace, change))
+			}
+
+			if sErr != nil {
+				s.fail(fmt.Errorf("cluster '%s': flow '%s': %w", name, r.Flow, sErr))
+			}
+		}
+
+		for _, r := range actual {
+			if known[runnerKey(name, r.Flow, r.Namespace)] {
+				continue
+			}
+
+			// a runner being provisioned by a request handler is not persisted yet
+			if time.Since(r.Created) < interval {
+				continue
+			}
+
+			if dErr := deprovisionRunner(ctx, c, r); dErr != nil {
+				s.fail(fmt.Errorf("cluster '%s': flow '%s': %w", name, r.Flow, dErr))
+
+				continue
+			}
+
+			s.changes = append(s.changes, fmt.Sprintf("%s/%s: runner/%s deleted", name, r.Namespace, r.Flow))
+		}
+	}
+
+	l := slog.Info
+	if len(s.errors) > 0 {
+		l = slog.Warn
+	}
+
+	l("reconciliation finished",
+		slog.Group("summary",
+			slog.Int("clusters", s.clusters),
+			slog.Int("runners", s.runners),
+			slog.Int("changes", len(s.changes)),
+			slog.Int("errors", len(s.errors)),
+		),
+		slog.Any("changes", s.changes),
+		slog.Any("errors", errors.Join(s.errors...)),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+func runnerKey(cluster, flow, namespace string) string {
+	return cluster + "/" + namespace + "/" + flow
+}
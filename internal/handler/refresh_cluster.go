@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+// refreshCluster contacts the cluster again and records its inventory.
+func (h *Handler) refreshCluster(ctx *fiber.Ctx) error {
+	var req model.RefreshClusterRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	cluster, version, err := h.ClusterRepository.Get(ctx.Context(), req.Params.ClusterName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	c, err := h.newK8S(ctx.Context(), cluster)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	cluster.Inventory = inventory(ctx.Context(), c, cluster.Inventory)
+
+	err = h.ClusterRepository.Update(ctx.Context(), req.Params.ClusterName, *cluster, version)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in storage for update")
+	}
+	if err != nil && errors.Is(err, storage.ErrConflict) {
+		return fiber.NewError(fiber.StatusConflict, "cluster was modified concurrently, retry the request")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update cluster from storage: %w", err)
+	}
+
+	return ctx.JSON(model.Cluster{
+		Name:       cluster.Name,
+		Type:       cluster.Type,
+		Namespaces: cluster.Namespaces,
+		AuthMethod: cluster.AuthMethod,
+		Inventory:  cluster.Inventory,
+	})
+}
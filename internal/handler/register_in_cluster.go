@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/jetbuild/engine/internal/k8s"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+// RegisterInCluster registers the cluster the engine runs in under the name, unless it is registered
+// already. Missing permissions are logged rather than refused, because the service account of the engine
+// is usually granted after its first start.
+func (h *Handler) RegisterInCluster(ctx context.Context, name string) error {
+	c, err := k8s.NewInCluster(name)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	permissions, err := c.CheckPermissions(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if i := slices.IndexFunc(permissions, func(p model.Permission) bool {
+		return !p.Allowed
+	}); i >= 0 {
+		slog.Warn("in-cluster service account is missing permissions",
+			slog.String("cluster", name),
+			slog.String("resource", permissions[i].Resource),
+			slog.String("verb", permissions[i].Verb),
+		)
+	}
+
+	err = h.saveCluster(ctx, c, model.ClusterTypeInCluster, nil)
+	if err != nil && errors.Is(err, storage.ErrItemAlreadyExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	slog.Info("in-cluster cluster registered", slog.String("cluster", name))
+
+	return nil
+}
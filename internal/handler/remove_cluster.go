@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+	"github.com/jetbuild/engine/pkg/flow"
+)
+
+// removeCluster refuses to remove a cluster which still has runners, unless it is forced. A forced
+// removal tears down the runners of the cluster on a best effort basis and removes them from their flows.
+func (h *Handler) removeCluster(ctx *fiber.Ctx) error {
+	var req model.RemoveClusterRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	cluster, _, err := h.ClusterRepository.Get(ctx.Context(), req.Params.ClusterName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	flows, err := h.FlowRepository.List(ctx.Context())
+	if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+		return fmt.Errorf("failed to list flows from storage: %w", err)
+	}
+
+	isClusterRunner := func(r flow.Runner) bool {
+		return r.Cluster == req.Params.ClusterName
+	}
+
+	var names []string
+	for name, f := range flows {
+		if slices.ContainsFunc(f.Runners, isClusterRunner) {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) > 0 && !req.Query.Force {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("cluster is used by runners of %d flow(s)", len(names)))
+	}
+
+	for _, name := range names {
+		f, version, gErr := h.FlowRepository.Get(ctx.Context(), name)
+		if gErr != nil && errors.Is(gErr, storage.ErrKeyNotFound) {
+			continue
+		}
+		if gErr != nil {
+			return fmt.Errorf("failed to get flow from storage: %w", gErr)
+		}
+
+		for _, r := range f.Runners {
+			if !isClusterRunner(r) {
+				continue
+			}
+
+			if rErr := h.teardownRunner(ctx.Context(), cluster, *f, r); rErr != nil {
+				slog.Warn("failed to remove runner of removed cluster", "cluster", req.Params.ClusterName, "flow", name, "error", rErr)
+			}
+		}
+
+		f.Runners = slices.DeleteFunc(f.Runners, isClusterRunner)
+
+		err = h.FlowRepository.Update(h.authorContext(ctx), name, *f, version)
+		if err != nil && errors.Is(err, storage.ErrConflict) {
+			return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("flow '%s' was modified concurrently, retry the request", name))
+		}
+		if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+			return fmt.Errorf("failed to update flow from storage: %w", err)
+		}
+	}
+
+	err = h.ClusterRepository.Remove(ctx.Context(), req.Params.ClusterName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove cluster from storage: %w", err)
+	}
+
+	h.evictK8S(req.Params.ClusterName)
+
+	ctx.Status(fiber.StatusNoContent)
+
+	return nil
+}
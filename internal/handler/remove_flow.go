@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+// removeFlow tears down the runners of the flow before removing it. Runners which cannot be torn down
+// are left to the reconciler, which removes the workloads of unknown flows.
+func (h *Handler) removeFlow(ctx *fiber.Ctx) error {
+	var req model.RemoveFlowRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	f, _, err := h.FlowRepository.Get(ctx.Context(), req.Params.FlowName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get flow from storage: %w", err)
+	}
+
+	for _, r := range f.Runners {
+		cluster, _, cErr := h.ClusterRepository.Get(ctx.Context(), r.Cluster)
+		if cErr != nil && errors.Is(cErr, storage.ErrKeyNotFound) {
+			continue
+		}
+
+		if cErr == nil {
+			cErr = h.teardownRunner(ctx.Context(), cluster, *f, r)
+		}
+		if cErr != nil {
+			slog.Warn("failed to remove runner of removed flow", "flow", f.Name, "cluster", r.Cluster, "error", cErr)
+		}
+	}
+
+	err = h.FlowRepository.Remove(ctx.Context(), req.Params.FlowName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove flow from storage: %w", err)
+	}
+
+	ctx.Status(fiber.StatusNoContent)
+
+	return nil
+}
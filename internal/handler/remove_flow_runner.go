@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+// removeFlowRunner removes the runner from the flow before tearing down its kubernetes resources, so
+// the reconciler removes them later when the tear down fails. A runner whose cluster does not exist anymore
+// is only removed from the flow.
+func (h *Handler) removeFlowRunner(ctx *fiber.Ctx) error {
+	var req model.RemoveFlowRunnerRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	fr, err := h.findRunner(ctx.Context(), req.Params.FlowName, req.Params.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	cluster, _, err := h.ClusterRepository.Get(ctx.Context(), req.Params.ClusterName)
+	if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	r := fr.runner()
+	f := *fr.flow
+	f.Runners = slices.Delete(slices.Clone(f.Runners), fr.index, fr.index+1)
+
+	err = h.FlowRepository.Update(h.authorContext(ctx), req.Params.FlowName, f, fr.version)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage for update")
+	}
+	if err != nil && errors.Is(err, storage.ErrConflict) {
+		return fiber.NewError(fiber.StatusConflict, "flow was modified concurrently, retry the request")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update flow from storage: %w", err)
+	}
+
+	if cluster == nil {
+		slog.Warn("cluster of the runner does not found in storage, its resources are left in place", "flow", f.Name, "cluster", r.Cluster)
+	} else if err = h.teardownRunner(ctx.Context(), cluster, f, r); err != nil {
+		slog.Warn("failed to remove runner", "flow", f.Name, "cluster", r.Cluster, "error", err)
+	}
+
+	ctx.Status(fiber.StatusNoContent)
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+// rewrapClusters wraps the kube config data keys of every cluster with the latest transit key version
+// after a key rotation. Kube configs stored before transit was enabled are encrypted as well.
+func (h *Handler) rewrapClusters(ctx *fiber.Ctx) error {
+	if h.Transit == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "transit encryption is not enabled")
+	}
+
+	res := model.RewrapClustersResponse{
+		Items: make([]string, 0),
+	}
+
+	clusters, err := h.ClusterRepository.List(ctx.Context())
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return ctx.JSON(res)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	for name := range clusters {
+		cluster, version, gErr := h.ClusterRepository.Get(ctx.Context(), name)
+		if gErr != nil {
+			return fmt.Errorf("failed to get cluster '%s' from storage: %w", name, gErr)
+		}
+
+		if cluster.IsInCluster() {
+			continue
+		}
+
+		if envelope, ok := cluster.Config.(string); ok {
+			cluster.Config, err = h.Transit.Rewrap(ctx.Context(), envelope)
+		} else {
+			cluster.Config, err = h.sealConfig(ctx.Context(), cluster.Config)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to rewrap cluster '%s' kube config: %w", name, err)
+		}
+
+		err = h.ClusterRepository.Update(ctx.Context(), name, *cluster, version)
+		if err != nil && errors.Is(err, storage.ErrConflict) {
+			return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("cluster '%s' was modified concurrently, retry the request", name))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update cluster '%s' from storage: %w", name, err)
+		}
+
+		h.evictK8S(name)
+
+		res.Items = append(res.Items, name)
+	}
+
+	return ctx.JSON(res)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+// rollbackFlow writes the components of a previous flow version as a new version, once they are validated
+// against the current catalog. Runners are kept as they are, since they describe the current state of the
+// clusters rather than the definition, and are synced to the restored definition.
+func (h *Handler) rollbackFlow(ctx *fiber.Ctx) error {
+	var req model.RollbackFlowRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	f, version, err := h.FlowRepository.Get(ctx.Context(), req.Params.FlowName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get flow from storage: %w", err)
+	}
+
+	if req.Body.Version == version {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("flow version '%d' is already the current version", version))
+	}
+
+	old, err := h.FlowRepository.GetVersion(ctx.Context(), req.Params.FlowName, req.Body.Version)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("flow version '%d' does not found in storage", req.Body.Version))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get flow version from storage: %w", err)
+	}
+
+	// the previous version may use components which are no longer in the catalog
+	rf := newFlowRequest(*old)
+	if err = rf.Validate(h.Catalog.Components()); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, fmt.Sprintf("flow version '%d' is not valid anymore: %s", req.Body.Version, err))
+	}
+
+	f.Components = newFlow(rf).Components
+
+	err = h.FlowRepository.Update(h.authorContext(ctx), req.Params.FlowName, *f, version)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage for update")
+	}
+	if err != nil && errors.Is(err, storage.ErrConflict) {
+		return fiber.NewError(fiber.StatusConflict, "flow was modified concurrently, retry the request")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update flow from storage: %w", err)
+	}
+
+	h.syncRunners(ctx.Context(), *f)
+
+	return ctx.JSON(f)
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/k8s"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+	"github.com/jetbuild/engine/pkg/flow"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type flowRunner struct {
+	flow    *flow.Flow
+	version int64
+	index   int
+	cluster *model.Cluster
+}
+
+func (fr *flowRunner) runner() flow.Runner {
+	return fr.flow.Runners[fr.index]
+}
+
+// findFlowRunner returns the runner of the flow in the cluster, together with the flow version and the cluster.
+func (h *Handler) findFlowRunner(ctx context.Context, flowName, clusterName string) (*flowRunner, error) {
+	fr, err := h.findRunner(ctx, flowName, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, _, err := h.ClusterRepository.Get(ctx, clusterName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "cluster does not found in storage")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	fr.cluster = cluster
+
+	return fr, nil
+}
+
+// findRunner returns the runner of the flow in the cluster together with the flow version, without the cluster,
+// which may not exist anymore.
+func (h *Handler) findRunner(ctx context.Context, flowName, clusterName string) (*flowRunner, error) {
+	f, version, err := h.FlowRepository.Get(ctx, flowName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "flow does not found in storage")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow from storage: %w", err)
+	}
+
+	i := slices.IndexFunc(f.Runners, func(r flow.Runner) bool {
+		return r.Cluster == clusterName
+	})
+	if i < 0 {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("flow does not have a runner in cluster '%s'", clusterName))
+	}
+
+	return &flowRunner{
+		flow:    f,
+		version: version,
+		index:   i,
+	}, nil
+}
+
+func (h *Handler) newRunner(f flow.Flow, r flow.Runner) (k8s.Runner, error) {
+	f.Runners = nil
+
+	d, err := json.Marshal(f)
+	if err != nil {
+		return k8s.Runner{}, fmt.Errorf("failed to marshal flow definition: %w", err)
+	}
+
+	return k8s.Runner{
+		Flow:        f.Name,
+		Namespace:   r.Namespace,
+		Image:       fmt.Sprintf("ghcr.io/%s/runner:%s", strings.ToLower(h.Config.GithubOrganization), r.Version),
+		Version:     r.Version,
+		Definition:  d,
+		MinReplicas: r.MinReplicas,
+		MaxReplicas: r.MaxReplicas,
+	}, nil
+}
+
+// syncRunners applies the flow to the workloads of its runners, so that they do not keep running the previous
+// definition until the next reconciliation. Failures are only logged, as the reconciler syncs them later.
+func (h *Handler) syncRunners(ctx context.Context, f flow.Flow) {
+	for _, r := range f.Runners {
+		if err := h.syncRunner(ctx, f, r); err != nil {
+			slog.Warn("failed to sync runner", "flow", f.Name, "cluster", r.Cluster, "error", err)
+		}
+	}
+}
+
+func (h *Handler) syncRunner(ctx context.Context, f flow.Flow, r flow.Runner) error {
+	cluster, _, err := h.ClusterRepository.Get(ctx, r.Cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	if !cluster.AllowsNamespace(r.Namespace) {
+		return fmt.Errorf("namespace '%s' is not allowed in cluster '%s'", r.Namespace, r.Cluster)
+	}
+
+	c, err := h.newK8S(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	kr, err := h.newRunner(f, r)
+	if err != nil {
+		return err
+	}
+
+	if _, err = c.SyncRunner(ctx, kr); err != nil {
+		return fmt.Errorf("failed to sync runner: %w", err)
+	}
+
+	return nil
+}
+
+// checkReplicas rejects replica bounds which are inverted once the missing ones fall back to the defaults.
+func checkReplicas(minReplicas, maxReplicas int32) error {
+	lo, hi := k8s.RunnerReplicas(minReplicas, maxReplicas)
+	if hi < lo {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("'maxReplicas' %d cannot be less than 'minReplicas' %d", hi, lo))
+	}
+
+	return nil
+}
+
+// teardownRunner removes the kubernetes resources of the flow runner from the cluster.
+func (h *Handler) teardownRunner(ctx context.Context, cluster *model.Cluster, f flow.Flow, r flow.Runner) error {
+	c, err := h.newK8S(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	kr, err := h.newRunner(f, r)
+	if err != nil {
+		return err
+	}
+
+	return deprovisionRunner(ctx, c, kr)
+}
+
+// provisionRunner creates every kubernetes resource of the runner. When one of them
+// fails, the resources created before it are removed again.
+func provisionRunner(ctx context.Context, c k8s.K8S, r k8s.Runner) error {
+	var created []func(context.Context, k8s.Runner) error
+
+	steps := []struct {
+		name   string
+		create func(context.Context, k8s.Runner) error
+		delete func(context.Context, k8s.Runner) error
+	}{
+		{name: "config map", create: c.CreateConfigMap, delete: c.DeleteConfigMap},
+		{name: "deployment", create: c.CreateDeployment, delete: c.DeleteDeployment},
+		{name: "hpa", create: c.CreateHPA, delete: c.DeleteHPA},
+	}
+
+	for _, s := range steps {
+		if err := s.create(ctx, r); err != nil {
+			return errors.Join(fmt.Errorf("failed to create %s: %w", s.name, err), rollbackRunner(ctx, r, created))
+		}
+
+		created = append(created, s.delete)
+	}
+
+	return nil
+}
+
+// deprovisionRunner removes every kubernetes resource of the runner.
+func deprovisionRunner(ctx context.Context, c k8s.K8S, r k8s.Runner) error {
+	return rollbackRunner(ctx, r, []func(context.Context, k8s.Runner) error{
+		c.DeleteConfigMap,
+		c.DeleteDeployment,
+		c.DeleteHPA,
+	})
+}
+
+func rollbackRunner(ctx context.Context, r k8s.Runner, deletes []func(context.Context, k8s.Runner) error) error {
+	var errs []error
+
+	for i := len(deletes) - 1; i >= 0; i-- {
+		if err := deletes[i](ctx, r); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		slog.Error("failed to remove runner resources", "flow", r.Flow, "namespace", r.Namespace, "error", errors.Join(errs...))
+
+		return fmt.Errorf("failed to remove runner resources: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
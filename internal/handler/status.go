@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/pkg/flow"
+)
+
+// runnerStatusTimeout bounds the status of a runner, so that an unreachable cluster does not hold the response.
+const runnerStatusTimeout = 10 * time.Second
+
+// runnerStatus returns the live status of the runner, which is unknown when it cannot be computed in time.
+func (h *Handler) runnerStatus(ctx context.Context, f flow.Flow, r flow.Runner) *model.RunnerStatus {
+	ctx, cancel := context.WithTimeout(ctx, runnerStatusTimeout)
+	defer cancel()
+
+	unknown := func(err error) *model.RunnerStatus {
+		return &model.RunnerStatus{
+			Health:  model.HealthUnknown,
+			Message: err.Error(),
+		}
+	}
+
+	cluster, _, err := h.ClusterRepository.Get(ctx, r.Cluster)
+	if err != nil {
+		return unknown(fmt.Errorf("failed to get cluster: %w", err))
+	}
+
+	c, err := h.newK8S(ctx, cluster)
+	if err != nil {
+		return unknown(fmt.Errorf("failed to create kubernetes client: %w", err))
+	}
+
+	kr, err := h.newRunner(f, r)
+	if err != nil {
+		return unknown(err)
+	}
+
+	s, err := c.GetRunnerStatus(ctx, kr)
+	if err != nil {
+		return unknown(fmt.Errorf("failed to get runner status: %w", err))
+	}
+
+	return s
+}
+
+// newFlowResponse returns the flow, with the live status of its runners and the aggregated flow health
+// when the status is requested.
+func (h *Handler) newFlowResponse(ctx context.Context, f flow.Flow, status bool) model.Flow {
+	return h.newFlowResponses(ctx, []flow.Flow{f}, status)[0]
+}
+
+// newFlowResponses returns the responses of the flows. Runners are queried in parallel, at most status
+// concurrency at once, and the ones which do not answer in time are reported with an unknown status.
+func (h *Handler) newFlowResponses(ctx context.Context, flows []flow.Flow, status bool) []model.Flow {
+	res := make([]model.Flow, len(flows))
+	for i, f := range flows {
+		res[i].Flow = f
+
+		for _, r := range f.Runners {
+			res[i].Runners = append(res[i].Runners, model.FlowRunner{
+				Runner: r,
+			})
+		}
+	}
+
+	if !status {
+		return res
+	}
+
+	sem := make(chan struct{}, max(h.StatusConcurrency, 1))
+
+	var wg sync.WaitGroup
+	for i := range res {
+		for j := range res[i].Runners {
+			wg.Add(1)
+			sem <- struct{}{}
+
+			go func(f flow.Flow, fr *model.FlowRunner) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				fr.Status = h.runnerStatus(ctx, f, fr.Runner)
+			}(flows[i], &res[i].Runners[j])
+		}
+	}
+	wg.Wait()
+
+	for i := range res {
+		res[i].Health = model.HealthUnknown
+		for j, r := range res[i].Runners {
+			if j == 0 {
+				res[i].Health = r.Status.Health
+
+				continue
+			}
+
+			res[i].Health = res[i].Health.Worse(r.Status.Health)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/k8s"
+	"github.com/jetbuild/engine/internal/model"
+)
+
+const logsHeartbeatInterval = 15 * time.Second
+
+// streamFlowRunnerLogs streams the runner pod logs as chunked plain text, or as server-sent events when
+// the client accepts them.
+func (h *Handler) streamFlowRunnerLogs(ctx *fiber.Ctx) error {
+	var req model.StreamFlowRunnerLogsRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	fr, err := h.findFlowRunner(ctx.Context(), req.Params.FlowName, req.Params.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	c, err := h.newK8S(ctx.Context(), fr.cluster)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	r, err := h.newRunner(*fr.flow, fr.runner())
+	if err != nil {
+		return err
+	}
+
+	// the stream outlives the handler, it is cancelled once a write to the client fails
+	sctx, cancel := context.WithCancel(context.Background())
+
+	s, err := c.StreamRunnerLogs(sctx, r, k8s.LogOptions{
+		Pod:        req.Query.Pod,
+		Container:  req.Query.Container,
+		Follow:     req.Query.Follow,
+		Since:      req.Since,
+		Tail:       req.Query.Tail,
+		Timestamps: req.Query.Timestamps,
+	})
+	if err != nil && errors.Is(err, k8s.ErrPodNotFound) {
+		cancel()
+
+		return fiber.NewError(fiber.StatusNotFound, "runner pod does not found")
+	}
+	if err != nil {
+		cancel()
+
+		return fmt.Errorf("failed to stream runner logs: %w", err)
+	}
+
+	sse := strings.Contains(ctx.Get(fiber.HeaderAccept), "text/event-stream")
+
+	if sse {
+		ctx.Set(fiber.HeaderContentType, "text/event-stream")
+		ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	} else {
+		ctx.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	}
+
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// closing the stream unblocks the scanner once the client goes away
+		defer cancel()
+		defer s.Close()
+
+		lines := make(chan string)
+		go func() {
+			defer close(lines)
+
+			sc := bufio.NewScanner(s)
+			for sc.Scan() {
+				select {
+				case lines <- sc.Text():
+				case <-sctx.Done():
+					return
+				}
+			}
+
+			if sErr := sc.Err(); sErr != nil && sctx.Err() == nil {
+				slog.Warn("failed to read runner logs", "flow", r.Flow, "cluster", req.Params.ClusterName, "error", sErr)
+			}
+		}()
+
+		// heartbeats detect clients which went away while no log line is written, plain text streams get an
+		// empty line as they cannot carry comments
+		t := time.NewTicker(logsHeartbeatInterval)
+		defer t.Stop()
+
+		for {
+			var wErr error
+
+			select {
+			case l, ok := <-lines:
+				if !ok {
+					return
+				}
+
+				if sse {
+					_, wErr = fmt.Fprintf(w, "data: %s\n\n", l)
+				} else {
+					_, wErr = fmt.Fprintln(w, l)
+				}
+			case <-t.C:
+				if sse {
+					_, wErr = fmt.Fprint(w, ": heartbeat\n\n")
+				} else {
+					_, wErr = fmt.Fprintln(w)
+				}
+			}
+
+			if wErr == nil {
+				wErr = w.Flush()
+			}
+			if wErr != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
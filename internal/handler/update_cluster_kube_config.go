@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/k8s"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+func (h *Handler) updateClusterKubeConfig(ctx *fiber.Ctx) error {
+	var req model.UpdateClusterKubeConfigRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	cluster, version, err := h.ClusterRepository.Get(ctx.Context(), req.Params.ClusterName)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	if cluster.IsInCluster() {
+		return fiber.NewError(fiber.StatusConflict, "in-cluster cluster does not have a kube config")
+	}
+
+	f, err := ctx.FormFile("kubeConfig")
+	if err != nil {
+		return fmt.Errorf("failed to get kube config file: %w", err)
+	}
+
+	c, err := k8s.NewFromFormFile(f, req.Body.Context)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	// the kube config may only rotate the credentials of the cluster, it must not point it elsewhere
+	if name := c.GetClusterName(); name != cluster.Name {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("kube config is of cluster '%s' instead of '%s'", name, cluster.Name))
+	}
+
+	if server := h.storedServer(ctx.Context(), cluster); len(server) > 0 && server != c.GetServer() {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("kube config server '%s' differs from the cluster server '%s'", c.GetServer(), server))
+	}
+
+	// the allow-list is kept unless the request replaces it
+	if req.Body.Namespaces != nil {
+		cluster.Namespaces = req.Body.Namespaces
+	}
+
+	res, ok, err := h.checkPermissions(ctx, c, cluster.Name, cluster.Namespaces)
+	if !ok {
+		return err
+	}
+
+	cluster.AuthMethod = c.GetAuthMethod()
+	cluster.Inventory = inventory(ctx.Context(), c, cluster.Inventory)
+
+	cluster.Config, err = h.sealConfig(ctx.Context(), c.GetConfig())
+	if err != nil {
+		return err
+	}
+
+	err = h.ClusterRepository.Update(ctx.Context(), req.Params.ClusterName, *cluster, version)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "cluster does not found in storage for update")
+	}
+	if err != nil && errors.Is(err, storage.ErrConflict) {
+		return fiber.NewError(fiber.StatusConflict, "cluster was modified concurrently, retry the request")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update cluster from storage: %w", err)
+	}
+
+	h.evictK8S(req.Params.ClusterName)
+
+	return ctx.JSON(res)
+}
+
+// storedServer returns the server of the stored kube config, or the last known endpoint of the cluster when
+// the stored kube config cannot be loaded anymore.
+func (h *Handler) storedServer(ctx context.Context, cluster *model.Cluster) string {
+	c, err := h.createK8S(ctx, cluster)
+	if err == nil {
+		return c.GetServer()
+	}
+
+	if cluster.Inventory != nil {
+		return cluster.Inventory.Endpoint
+	}
+
+	return ""
+}
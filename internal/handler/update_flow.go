@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+func (h *Handler) updateFlow(ctx *fiber.Ctx) error {
+	var req model.UpdateFlowRequest
+	if err := req.Bind(ctx, h.Validator, h.Catalog.Components()); err != nil {
+		return err
+	}
+
+	return h.replaceFlow(ctx, req.Params.FlowName, func(model.AddFlowRequest) (*model.AddFlowRequest, error) {
+		return &req.Body, nil
+	})
+}
+
+// replaceFlow replaces the definition of the flow with the one returned by the update function, which
+// is called with the current definition. The runners of the flow are kept as they are, and synced to the
+// new definition.
+func (h *Handler) replaceFlow(ctx *fiber.Ctx, name string, update func(model.AddFlowRequest) (*model.AddFlowRequest, error)) error {
+	f, version, err := h.FlowRepository.Get(ctx.Context(), name)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get flow from storage: %w", err)
+	}
+
+	expected, ok, err := ifMatchVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ok && expected != version {
+		return fiber.NewError(fiber.StatusPreconditionFailed, "flow version does not match if-match header")
+	}
+
+	req, err := update(newFlowRequest(*f))
+	if err != nil {
+		return err
+	}
+
+	n := newFlow(*req)
+	n.Runners = f.Runners
+
+	err = h.FlowRepository.Update(h.authorContext(ctx), name, n, version)
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage for update")
+	}
+	if err != nil && errors.Is(err, storage.ErrConflict) && ok {
+		return fiber.NewError(fiber.StatusPreconditionFailed, "flow version does not match if-match header")
+	}
+	if err != nil && errors.Is(err, storage.ErrConflict) {
+		return fiber.NewError(fiber.StatusConflict, "flow was modified concurrently, retry the request")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update flow from storage: %w", err)
+	}
+
+	h.syncRunners(ctx.Context(), n)
+
+	return ctx.JSON(n)
+}
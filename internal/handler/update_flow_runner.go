@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jetbuild/engine/internal/model"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+// updateFlowRunner performs a rolling update of the runner to the requested version and replica bounds.
+// The previous state is applied again when the update cannot be recorded in the flow.
+func (h *Handler) updateFlowRunner(ctx *fiber.Ctx) error {
+	var req model.UpdateFlowRunnerRequest
+	if err := req.Bind(ctx, h.Validator); err != nil {
+		return err
+	}
+
+	fr, err := h.findFlowRunner(ctx.Context(), req.Params.FlowName, req.Params.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	old := fr.runner()
+	runner := old
+
+	switch req.Body.Version {
+	case "":
+	case "latest":
+		runner.Version = h.LatestRunnerVersion
+	default:
+		runner.Version = strings.TrimLeft(req.Body.Version, "v")
+	}
+
+	if req.Body.MinReplicas != nil {
+		runner.MinReplicas = *req.Body.MinReplicas
+	}
+
+	if req.Body.MaxReplicas != nil {
+		runner.MaxReplicas = *req.Body.MaxReplicas
+	}
+
+	if err = checkReplicas(runner.MinReplicas, runner.MaxReplicas); err != nil {
+		return err
+	}
+
+	c, err := h.newK8S(ctx.Context(), fr.cluster)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	r, err := h.newRunner(*fr.flow, runner)
+	if err != nil {
+		return err
+	}
+
+	prev, err := h.newRunner(*fr.flow, old)
+	if err != nil {
+		return err
+	}
+
+	restore := func() {
+		if _, rErr := c.SyncRunner(ctx.Context(), prev); rErr != nil {
+			slog.Error("failed to restore runner", "flow", fr.flow.Name, "cluster", old.Cluster, "error", rErr)
+		}
+	}
+
+	if _, err = c.SyncRunner(ctx.Context(), r); err != nil {
+		restore()
+
+		return fmt.Errorf("failed to update runner: %w", err)
+	}
+
+	fr.flow.Runners[fr.index] = runner
+
+	err = h.FlowRepository.Update(h.authorContext(ctx), req.Params.FlowName, *fr.flow, fr.version)
+	if err != nil {
+		restore()
+	}
+	if err != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "flow does not found in storage for update")
+	}
+	if err != nil && errors.Is(err, storage.ErrConflict) {
+		return fiber.NewError(fiber.StatusConflict, "flow was modified concurrently, retry the request")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update flow from storage: %w", err)
+	}
+
+	return ctx.JSON(runner)
+}
@@ -3,17 +3,18 @@ package k8s
 import (
 	"context"
 
-	v1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func (k *k8s) CreateDeployment(ctx context.Context, namespace string) error {
-	d := v1.Deployment{}
-
-	_, err := k.client.AppsV1().Deployments(namespace).Create(ctx, &d, metav1.CreateOptions{})
+func (k *k8s) CreateDeployment(ctx context.Context, r Runner) error {
+	_, err := k.client.AppsV1().Deployments(r.Namespace).Create(ctx, r.deployment(), metav1.CreateOptions{})
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (k *k8s) DeleteDeployment(ctx context.Context, r Runner) error {
+	return k.client.AppsV1().Deployments(r.Namespace).Delete(ctx, r.name(), metav1.DeleteOptions{})
+}
@@ -4,27 +4,94 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jetbuild/engine/internal/model"
 	authorizationv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func (k *k8s) HasAdminPrivileges(ctx context.Context) (bool, error) {
-	auth, err := k.client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
-		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   "*",
-				Verb:        "*",
-				Group:       "*",
-				Version:     "*",
-				Resource:    "*",
-				Subresource: "*",
-				Name:        "*",
-			},
-		},
-	}, metav1.CreateOptions{})
-	if err != nil {
-		return false, fmt.Errorf("failed to create self subject access review: %w", err)
+type permission struct {
+	group       string
+	resource    string
+	subresource string
+	verbs       []string
+
+	// clusterScoped permissions are only required when the engine manages every namespace of the cluster.
+	clusterScoped bool
+
+	// optional permissions are granted to the service account, but a kube config may lack them.
+	optional bool
+}
+
+// permissions are every permission the engine needs to manage the runners of a cluster.
+var permissions = []permission{
+	{resource: "namespaces", verbs: []string{"get", "list", "create"}, clusterScoped: true},
+	{group: "apps", resource: "deployments", verbs: []string{"get", "list", "create", "update", "delete"}},
+	{group: "autoscaling", resource: "horizontalpodautoscalers", verbs: []string{"get", "create", "update", "delete"}},
+	{resource: "configmaps", verbs: []string{"get", "create", "update", "delete"}},
+	{resource: "secrets", verbs: []string{"get", "create", "delete"}},
+	{resource: "serviceaccounts", verbs: []string{"get", "create", "delete"}},
+	{group: "rbac.authorization.k8s.io", resource: "roles", verbs: []string{"get", "create", "delete"}},
+	{group: "rbac.authorization.k8s.io", resource: "rolebindings", verbs: []string{"get", "create", "delete"}},
+	{resource: "pods", verbs: []string{"get", "list"}},
+	{resource: "pods", subresource: "log", verbs: []string{"get"}},
+	{resource: "events", verbs: []string{"list", "watch"}},
+	// the nodes are only listed for the capacity of the cluster inventory
+	{resource: "nodes", verbs: []string{"list"}, clusterScoped: true, optional: true},
+}
+
+// CheckPermissions reviews every permission the engine needs, in every namespace of the allow-list. An
+// empty allow-list means the engine manages every namespace, which needs the cluster scoped permissions too.
+func (k *k8s) CheckPermissions(ctx context.Context, namespaces []string) ([]model.Permission, error) {
+	scopes := namespaces
+	if len(scopes) == 0 {
+		scopes = []string{metav1.NamespaceAll}
+	}
+
+	var report []model.Permission
+
+	for _, p := range permissions {
+		if p.clusterScoped && len(namespaces) > 0 {
+			continue
+		}
+
+		for _, namespace := range scopes {
+			if p.clusterScoped {
+				namespace = metav1.NamespaceAll
+			}
+
+			for _, verb := range p.verbs {
+				auth, err := k.client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+					Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+						ResourceAttributes: &authorizationv1.ResourceAttributes{
+							Namespace:   namespace,
+							Verb:        verb,
+							Group:       p.group,
+							Resource:    p.resource,
+							Subresource: p.subresource,
+						},
+					},
+				}, metav1.CreateOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("failed to create self subject access review: %w", err)
+				}
+
+				report = append(report, model.Permission{
+					Namespace:   namespace,
+					Group:       p.group,
+					Resource:    p.resource,
+					Subresource: p.subresource,
+					Verb:        verb,
+					Allowed:     auth.Status.Allowed,
+					Optional:    p.optional,
+					Reason:      auth.Status.Reason,
+				})
+			}
+
+			if p.clusterScoped {
+				break
+			}
+		}
 	}
 
-	return auth.Status.Allowed, nil
+	return report, nil
 }
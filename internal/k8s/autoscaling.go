@@ -3,17 +3,18 @@ package k8s
 import (
 	"context"
 
-	v1 "k8s.io/api/autoscaling/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func (k *k8s) CreateHPA(ctx context.Context, namespace string) error {
-	h := v1.HorizontalPodAutoscaler{}
-
-	_, err := k.client.AutoscalingV1().HorizontalPodAutoscalers(namespace).Create(ctx, &h, metav1.CreateOptions{})
+func (k *k8s) CreateHPA(ctx context.Context, r Runner) error {
+	_, err := k.client.AutoscalingV1().HorizontalPodAutoscalers(r.Namespace).Create(ctx, r.hpa(), metav1.CreateOptions{})
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (k *k8s) DeleteHPA(ctx context.Context, r Runner) error {
+	return k.client.AutoscalingV1().HorizontalPodAutoscalers(r.Namespace).Delete(ctx, r.name(), metav1.DeleteOptions{})
+}
@@ -23,3 +23,13 @@ func (k *k8s) CreateNamespace(ctx context.Context, name string) error {
 func (k *k8s) ListNamespaces(ctx context.Context) (*corev1.NamespaceList, error) {
 	return k.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 }
+
+func (k *k8s) CreateConfigMap(ctx context.Context, r Runner) error {
+	_, err := k.client.CoreV1().ConfigMaps(r.Namespace).Create(ctx, r.configMap(), metav1.CreateOptions{})
+
+	return err
+}
+
+func (k *k8s) DeleteConfigMap(ctx context.Context, r Runner) error {
+	return k.client.CoreV1().ConfigMaps(r.Namespace).Delete(ctx, r.name(), metav1.DeleteOptions{})
+}
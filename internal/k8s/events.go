@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jetbuild/engine/internal/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ListRunnerEvents returns the events of the runner resources, ordered by the time they were last seen.
+func (k *k8s) ListRunnerEvents(ctx context.Context, r Runner) ([]model.RunnerEvent, error) {
+	events, _, err := k.listRunnerEvents(ctx, r)
+
+	return events, err
+}
+
+// WatchRunnerEvents sends the current events of the runner resources, followed by the new ones, until the
+// context is done or the watch is closed by the cluster.
+func (k *k8s) WatchRunnerEvents(ctx context.Context, r Runner) (<-chan model.RunnerEvent, error) {
+	events, version, err := k.listRunnerEvents(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := k.client.CoreV1().Events(r.Namespace).Watch(ctx, metav1.ListOptions{
+		ResourceVersion: version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch events: %w", err)
+	}
+
+	c := make(chan model.RunnerEvent)
+
+	go func() {
+		defer close(c)
+		defer w.Stop()
+
+		send := func(e model.RunnerEvent) bool {
+			select {
+			case c <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for _, e := range events {
+			if !send(e) {
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case we, ok := <-w.ResultChan():
+				if !ok {
+					return
+				}
+
+				if we.Type != watch.Added && we.Type != watch.Modified {
+					continue
+				}
+
+				e, ok := we.Object.(*corev1.Event)
+				if !ok || !r.owns(e.InvolvedObject) {
+					continue
+				}
+
+				if !send(newRunnerEvent(e)) {
+					return
+				}
+			}
+		}
+	}()
+
+	return c, nil
+}
+
+func (k *k8s) listRunnerEvents(ctx context.Context, r Runner) ([]model.RunnerEvent, string, error) {
+	list, err := k.client.CoreV1().Events(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list events: %w", err)
+	}
+
+	events := make([]model.RunnerEvent, 0)
+	for i := range list.Items {
+		if r.owns(list.Items[i].InvolvedObject) {
+			events = append(events, newRunnerEvent(&list.Items[i]))
+		}
+	}
+
+	slices.SortFunc(events, func(a, b model.RunnerEvent) int {
+		return a.LastSeen.Compare(b.LastSeen)
+	})
+
+	return events, list.ResourceVersion, nil
+}
+
+// owns reports whether the object is one of the runner resources. Events do not carry the labels of
+// their objects, so replica sets and pods are matched by the names the deployment controller gives
+// them, which are '<deployment>-<hash>' and '<deployment>-<hash>-<suffix>'.
+func (r *Runner) owns(o corev1.ObjectReference) bool {
+	switch o.Kind {
+	case "Deployment", "HorizontalPodAutoscaler", "ConfigMap":
+		return o.Name == r.name()
+	case "ReplicaSet", "Pod":
+		suffix, ok := strings.CutPrefix(o.Name, r.name()+"-")
+		if !ok {
+			return false
+		}
+
+		dashes := 0
+		if o.Kind == "Pod" {
+			dashes = 1
+		}
+
+		return strings.Count(suffix, "-") == dashes
+	default:
+		return false
+	}
+}
+
+func newRunnerEvent(e *corev1.Event) model.RunnerEvent {
+	first, last := e.FirstTimestamp.Time, e.LastTimestamp.Time
+	if first.IsZero() {
+		first = e.EventTime.Time
+	}
+
+	if last.IsZero() {
+		last = e.EventTime.Time
+	}
+
+	count := e.Count
+	if e.Series != nil {
+		count = e.Series.Count
+		last = e.Series.LastObservedTime.Time
+	}
+
+	return model.RunnerEvent{
+		Type:      e.Type,
+		Reason:    e.Reason,
+		Message:   e.Message,
+		Kind:      e.InvolvedObject.Kind,
+		Object:    e.InvolvedObject.Name,
+		Count:     max(count, 1),
+		FirstSeen: first,
+		LastSeen:  last,
+	}
+}
@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jetbuild/engine/internal/model"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiversion "k8s.io/apimachinery/pkg/version"
+)
+
+// inventoryTimeout bounds the inventory, so that an unreachable cluster does not hold the request adding,
+// updating or refreshing it.
+const inventoryTimeout = 10 * time.Second
+
+// GetInventory describes the cluster from its discovery information and nodes. Namespace scoped
+// credentials are usually not allowed to list the nodes, which leaves the capacity unknown.
+func (k *k8s) GetInventory(ctx context.Context) (*model.ClusterInventory, error) {
+	ctx, cancel := context.WithTimeout(ctx, inventoryTimeout)
+	defer cancel()
+
+	// the discovery client does not take a context, so the version is requested directly
+	b, err := k.client.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+
+	var v apiversion.Info
+	if err = json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal server version: %w", err)
+	}
+
+	now := time.Now()
+
+	i := model.ClusterInventory{
+		ServerVersion: v.GitVersion,
+		Endpoint:      k.rest.Host,
+		LastContact:   &now,
+		Reachable:     true,
+	}
+
+	nodes, err := k.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if apierrors.IsForbidden(err) {
+		return &i, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	var cpu, memory resource.Quantity
+	for _, n := range nodes.Items {
+		cpu.Add(n.Status.Allocatable[corev1.ResourceCPU])
+		memory.Add(n.Status.Allocatable[corev1.ResourceMemory])
+	}
+
+	n := len(nodes.Items)
+
+	i.Nodes = &n
+	i.AllocatableCPU = cpu.String()
+	i.AllocatableMemory = memory.String()
+
+	return &i, nil
+}
@@ -5,34 +5,86 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 
-	"gopkg.in/yaml.v3"
+	"github.com/jetbuild/engine/internal/model"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/yaml"
 )
 
 type K8S interface {
 	GetConfig() any
 	GetClusterName() string
-	HasAdminPrivileges(ctx context.Context) (bool, error)
+	GetServer() string
+	GetAuthMethod() string
+	GetInventory(ctx context.Context) (*model.ClusterInventory, error)
+	CheckPermissions(ctx context.Context, namespaces []string) ([]model.Permission, error)
+	CreateServiceAccount(ctx context.Context, namespaces []string) (K8S, func(context.Context) error, error)
 	CreateNamespace(ctx context.Context, name string) error
 	ListNamespaces(ctx context.Context) (*corev1.NamespaceList, error)
-	CreateDeployment(ctx context.Context, namespace string) error
-	CreateHPA(ctx context.Context, namespace string) error
+	CreateConfigMap(ctx context.Context, r Runner) error
+	DeleteConfigMap(ctx context.Context, r Runner) error
+	CreateDeployment(ctx context.Context, r Runner) error
+	DeleteDeployment(ctx context.Context, r Runner) error
+	CreateHPA(ctx context.Context, r Runner) error
+	DeleteHPA(ctx context.Context, r Runner) error
+	ListRunners(ctx context.Context, namespaces []string) ([]Runner, error)
+	SyncRunner(ctx context.Context, r Runner) ([]string, error)
+	GetRunnerStatus(ctx context.Context, r Runner) (*model.RunnerStatus, error)
+	StreamRunnerLogs(ctx context.Context, r Runner, o LogOptions) (io.ReadCloser, error)
+	ListRunnerEvents(ctx context.Context, r Runner) ([]model.RunnerEvent, error)
+	WatchRunnerEvents(ctx context.Context, r Runner) (<-chan model.RunnerEvent, error)
+}
+
+// Decrypter decrypts kube configs which are stored encrypted.
+type Decrypter interface {
+	Decrypt(ctx context.Context, ciphertext string) ([]byte, error)
 }
 
 type k8s struct {
 	client      *kubernetes.Clientset
+	rest        *rest.Config
 	config      any
 	clusterName string
+	authMethod  string
 }
 
-func New(cfg any) (K8S, error) {
-	c, err := json.Marshal(cfg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load kube config file: %w", err)
+const (
+	AuthMethodClientCertificate = "client-certificate"
+	AuthMethodToken             = "token"
+	AuthMethodBasic             = "basic"
+	AuthMethodNone              = "none"
+)
+
+// New creates a client from a stored kube config. An encrypted kube config is stored as a string,
+// which is decrypted with the decrypter.
+func New(ctx context.Context, cfg any, d Decrypter) (K8S, error) {
+	var c []byte
+
+	switch v := cfg.(type) {
+	case string:
+		if d == nil {
+			return nil, errors.New("kube config is encrypted but no decrypter is configured")
+		}
+
+		p, err := d.Decrypt(ctx, v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt kube config file: %w", err)
+		}
+
+		c = p
+	default:
+		p, err := json.Marshal(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kube config file: %w", err)
+		}
+
+		c = p
 	}
 
 	cl, err := clientcmd.Load(c)
@@ -40,6 +92,16 @@ func New(cfg any) (K8S, error) {
 		return nil, fmt.Errorf("failed to load kube config file: %w", err)
 	}
 
+	// kube configs stored before they were minified on upload may have other contexts, which must not
+	// fail the validation of the context in use
+	if err = clientcmdapi.MinifyConfig(cl); err != nil {
+		return nil, fmt.Errorf("failed to minify kube config file: %w", err)
+	}
+
+	if err = validateConfig(cl); err != nil {
+		return nil, err
+	}
+
 	r, err := clientcmd.NewDefaultClientConfig(*cl, nil).ClientConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new default client config: %w", err)
@@ -53,19 +115,47 @@ func New(cfg any) (K8S, error) {
 	}
 
 	return &k8s{
-		client: s,
+		client:      s,
+		rest:        r,
+		clusterName: cl.Contexts[cl.CurrentContext].Cluster,
+		authMethod:  authMethod(cl),
 	}, nil
 }
 
-func NewFromFormFile(f *multipart.FileHeader) (K8S, error) {
+// NewInCluster creates a client of the cluster the engine runs in, authenticated as the service account
+// of its pod.
+func NewInCluster(name string) (K8S, error) {
+	r, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in cluster config: %w", err)
+	}
+
+	// TODO: set r.UserAgent
+
+	s, err := kubernetes.NewForConfig(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	return &k8s{
+		client:      s,
+		rest:        r,
+		clusterName: name,
+		authMethod:  AuthMethodToken,
+	}, nil
+}
+
+// NewFromFormFile creates a client from an uploaded kube config, bound to the given context or to the
+// current context when it is empty. Only that context is kept in the config which is stored.
+func NewFromFormFile(f *multipart.FileHeader, context string) (K8S, error) {
 	s, err := f.Open()
 	if err != nil {
 		return nil, fmt.Errorf("failed to open kube config file: %w", err)
 	}
 	defer s.Close()
 
-	p := make([]byte, f.Size)
-	if _, err = s.Read(p); err != nil {
+	p, err := io.ReadAll(s)
+	if err != nil {
 		return nil, fmt.Errorf("failed to read kube config file: %w", err)
 	}
 
@@ -78,6 +168,10 @@ func NewFromFormFile(f *multipart.FileHeader) (K8S, error) {
 		return nil, errors.New("kube config should have a context")
 	}
 
+	if len(context) > 0 {
+		c.CurrentContext = context
+	}
+
 	if len(c.CurrentContext) == 0 {
 		return nil, errors.New("kube config should have current context")
 	}
@@ -91,6 +185,14 @@ func NewFromFormFile(f *multipart.FileHeader) (K8S, error) {
 		return nil, fmt.Errorf("kube config '%s' context should have cluster", c.CurrentContext)
 	}
 
+	if err = clientcmdapi.MinifyConfig(c); err != nil {
+		return nil, fmt.Errorf("failed to minify kube config file: %w", err)
+	}
+
+	if err = validateConfig(c); err != nil {
+		return nil, err
+	}
+
 	r, err := clientcmd.NewDefaultClientConfig(*c, nil).ClientConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new default client config: %w", err)
@@ -103,18 +205,90 @@ func NewFromFormFile(f *multipart.FileHeader) (K8S, error) {
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
 
-	var cfg any
-	if err = yaml.Unmarshal(p, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal kube config file: %w", err)
+	cfg, err := storedConfig(c)
+	if err != nil {
+		return nil, err
 	}
 
 	return &k8s{
 		client:      client,
+		rest:        r,
 		config:      cfg,
 		clusterName: cx.Cluster,
+		authMethod:  authMethod(c),
 	}, nil
 }
 
+// validateConfig rejects kube configs which cannot work inside the engine, because they reference files
+// of the machine they were written on, or run credential plugins which are not installed in the engine.
+func validateConfig(c *clientcmdapi.Config) error {
+	for name, cl := range c.Clusters {
+		if len(cl.CertificateAuthority) > 0 {
+			return fmt.Errorf("kube config '%s' cluster should inline its certificate authority instead of referencing a file", name)
+		}
+	}
+
+	for name, a := range c.AuthInfos {
+		switch {
+		case a.Exec != nil:
+			return fmt.Errorf("kube config '%s' user runs the exec credential plugin '%s', which is not supported, use a token or a client certificate instead", name, a.Exec.Command)
+		case a.AuthProvider != nil:
+			return fmt.Errorf("kube config '%s' user uses the '%s' auth provider, which is not supported, use a token or a client certificate instead", name, a.AuthProvider.Name)
+		case len(a.ClientCertificate) > 0 || len(a.ClientKey) > 0:
+			return fmt.Errorf("kube config '%s' user should inline its client certificate and key instead of referencing files", name)
+		case len(a.TokenFile) > 0:
+			return fmt.Errorf("kube config '%s' user should inline its token instead of referencing a file", name)
+		}
+	}
+
+	return nil
+}
+
+// authMethod returns how the current context of a validated kube config authenticates.
+func authMethod(c *clientcmdapi.Config) string {
+	cx, ok := c.Contexts[c.CurrentContext]
+	if !ok {
+		return AuthMethodNone
+	}
+
+	a, ok := c.AuthInfos[cx.AuthInfo]
+	if !ok {
+		return AuthMethodNone
+	}
+
+	switch {
+	case len(a.ClientCertificateData) > 0:
+		return AuthMethodClientCertificate
+	case len(a.Token) > 0:
+		return AuthMethodToken
+	case len(a.Username) > 0:
+		return AuthMethodBasic
+	default:
+		return AuthMethodNone
+	}
+}
+
+// storedConfig returns the kube config in the form it is stored, which is its own json encoding so that
+// it is loaded back without loss.
+func storedConfig(c *clientcmdapi.Config) (any, error) {
+	p, err := clientcmd.Write(*c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write kube config file: %w", err)
+	}
+
+	j, err := yaml.YAMLToJSON(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert kube config file: %w", err)
+	}
+
+	var cfg any
+	if err = json.Unmarshal(j, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal kube config file: %w", err)
+	}
+
+	return cfg, nil
+}
+
 func (k *k8s) GetConfig() any {
 	return k.config
 }
@@ -122,3 +296,11 @@ func (k *k8s) GetConfig() any {
 func (k *k8s) GetClusterName() string {
 	return k.clusterName
 }
+
+func (k *k8s) GetServer() string {
+	return k.rest.Host
+}
+
+func (k *k8s) GetAuthMethod() string {
+	return k.authMethod
+}
@@ -0,0 +1,133 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var ErrPodNotFound = errors.New("pod not found")
+
+type LogOptions struct {
+	Pod        string
+	Container  string
+	Follow     bool
+	Since      time.Duration
+	Tail       *int64
+	Timestamps bool
+}
+
+type logStream struct {
+	*io.PipeReader
+	streams []io.ReadCloser
+}
+
+// StreamRunnerLogs streams the logs of the runner pod, or of every runner pod when no pod is given,
+// in which case every line is prefixed with the name of its pod.
+func (k *k8s) StreamRunnerLogs(ctx context.Context, r Runner, o LogOptions) (io.ReadCloser, error) {
+	pods, err := k.client.CoreV1().Pods(r.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(r.selector()).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var names []string
+	for _, p := range pods.Items {
+		if len(o.Pod) == 0 || p.Name == o.Pod {
+			names = append(names, p.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, ErrPodNotFound
+	}
+
+	opts := corev1.PodLogOptions{
+		Container:  o.Container,
+		Follow:     o.Follow,
+		TailLines:  o.Tail,
+		Timestamps: o.Timestamps,
+	}
+
+	if len(opts.Container) == 0 {
+		opts.Container = runnerContainerName
+	}
+
+	if o.Since > 0 {
+		seconds := int64(o.Since.Seconds())
+		opts.SinceSeconds = &seconds
+	}
+
+	var streams []io.ReadCloser
+
+	for _, name := range names {
+		s, sErr := k.client.CoreV1().Pods(r.Namespace).GetLogs(name, &opts).Stream(ctx)
+		if sErr != nil {
+			for _, c := range streams {
+				_ = c.Close()
+			}
+
+			return nil, fmt.Errorf("failed to stream pod '%s' logs: %w", name, sErr)
+		}
+
+		streams = append(streams, s)
+	}
+
+	if len(streams) == 1 && len(o.Pod) > 0 {
+		return streams[0], nil
+	}
+
+	pr, pw := io.Pipe()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for i, s := range streams {
+		wg.Add(1)
+
+		go func(name string, s io.Reader) {
+			defer wg.Done()
+
+			sc := bufio.NewScanner(s)
+			for sc.Scan() {
+				mu.Lock()
+				_, wErr := fmt.Fprintf(pw, "[%s] %s\n", name, sc.Text())
+				mu.Unlock()
+
+				if wErr != nil {
+					return
+				}
+			}
+		}(names[i], s)
+	}
+
+	go func() {
+		wg.Wait()
+		_ = pw.Close()
+	}()
+
+	return &logStream{
+		PipeReader: pr,
+		streams:    streams,
+	}, nil
+}
+
+func (l *logStream) Close() error {
+	errs := []error{l.PipeReader.Close()}
+	for _, s := range l.streams {
+		errs = append(errs, s.Close())
+	}
+
+	return errors.Join(errs...)
+}
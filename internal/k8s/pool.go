@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Pool reuses the clients of the clusters, so that requests do not pay for loading the kube config and
+// establishing new connections. A client is created again when the hash of its cluster changes, and is
+// dropped after being idle for the ttl.
+type Pool struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*pooledClient
+}
+
+type pooledClient struct {
+	hash   string
+	client K8S
+	used   time.Time
+}
+
+func NewPool(ttl time.Duration) *Pool {
+	return &Pool{
+		ttl:     ttl,
+		clients: make(map[string]*pooledClient),
+	}
+}
+
+// Get returns the pooled client of the cluster, or creates one with the function when there is none for
+// the hash.
+func (p *Pool) Get(name, hash string, create func() (K8S, error)) (K8S, error) {
+	p.mu.Lock()
+	if c, ok := p.clients[name]; ok && c.hash == hash {
+		c.used = time.Now()
+		p.mu.Unlock()
+
+		return c.client, nil
+	}
+	p.mu.Unlock()
+
+	// the client is created outside the lock, as it may take long and concurrent creations are harmless
+	client, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clients[name] = &pooledClient{
+		hash:   hash,
+		client: client,
+		used:   time.Now(),
+	}
+
+	return client, nil
+}
+
+// Evict drops the client of the cluster, which is done when the cluster is updated or removed.
+func (p *Pool) Evict(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.clients, name)
+}
+
+// Run drops the idle clients until the context is done.
+func (p *Pool) Run(ctx context.Context) {
+	t := time.NewTicker(p.ttl)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			p.evictIdle()
+		}
+	}
+}
+
+func (p *Pool) evictIdle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for name, c := range p.clients {
+		if time.Since(c.used) >= p.ttl {
+			delete(p.clients, name)
+		}
+	}
+}
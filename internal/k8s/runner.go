@@ -0,0 +1,284 @@
+package k8s
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	runnerContainerName        = "runner"
+	runnerFlowVolume           = "flow"
+	runnerFlowPath             = "/etc/jetbuild"
+	runnerFlowFile             = "flow.json"
+	runnerChecksumAnnotation   = "jetbuild.io/flow-checksum"
+	runnerMinReplicas          = int32(1)
+	runnerMaxReplicas          = int32(3)
+	runnerTargetCPUUtilization = int32(80)
+)
+
+type Runner struct {
+	Flow       string
+	Namespace  string
+	Image      string
+	Version    string
+	Definition []byte
+	Created    time.Time
+
+	// MinReplicas and MaxReplicas are the hpa bounds, zero values fall back to the defaults.
+	MinReplicas int32
+	MaxReplicas int32
+}
+
+func (r *Runner) name() string {
+	return fmt.Sprintf("%s-runner", r.Flow)
+}
+
+func (r *Runner) selector() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":     "runner",
+		"app.kubernetes.io/instance": r.Flow,
+	}
+}
+
+func (r *Runner) labels() map[string]string {
+	l := r.selector()
+	l["app.kubernetes.io/version"] = r.Version
+	l["app.kubernetes.io/managed-by"] = "jetbuild"
+
+	return l
+}
+
+func (r *Runner) objectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      r.name(),
+		Namespace: r.Namespace,
+		Labels:    r.labels(),
+	}
+}
+
+func (r *Runner) configMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: r.objectMeta(),
+		Data: map[string]string{
+			runnerFlowFile: string(r.Definition),
+		},
+	}
+}
+
+func (r *Runner) deployment() *appsv1.Deployment {
+	checksum := sha256.Sum256(r.Definition)
+
+	return &appsv1.Deployment{
+		ObjectMeta: r.objectMeta(),
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: r.selector(),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: r.labels(),
+					Annotations: map[string]string{
+						runnerChecksumAnnotation: hex.EncodeToString(checksum[:]),
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  runnerContainerName,
+							Image: r.Image,
+							Env: []corev1.EnvVar{
+								{
+									Name:  "FLOW_PATH",
+									Value: fmt.Sprintf("%s/%s", runnerFlowPath, runnerFlowFile),
+								},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("100m"),
+									corev1.ResourceMemory: resource.MustParse("128Mi"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("256Mi"),
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      runnerFlowVolume,
+									MountPath: runnerFlowPath,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: runnerFlowVolume,
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: r.name(),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// RunnerReplicas resolves the hpa bounds of a runner, where zero values fall back to the defaults.
+func RunnerReplicas(minReplicas, maxReplicas int32) (int32, int32) {
+	if minReplicas <= 0 {
+		minReplicas = runnerMinReplicas
+	}
+
+	if maxReplicas <= 0 {
+		maxReplicas = runnerMaxReplicas
+	}
+
+	return minReplicas, maxReplicas
+}
+
+func (r *Runner) hpa() *autoscalingv1.HorizontalPodAutoscaler {
+	minReplicas, maxReplicas := RunnerReplicas(r.MinReplicas, r.MaxReplicas)
+	targetCPU := runnerTargetCPUUtilization
+
+	// runners stored before the bounds were validated may have them inverted
+	maxReplicas = max(minReplicas, maxReplicas)
+
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: r.objectMeta(),
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       r.name(),
+			},
+			MinReplicas:                    &minReplicas,
+			MaxReplicas:                    maxReplicas,
+			TargetCPUUtilizationPercentage: &targetCPU,
+		},
+	}
+}
+
+// ListRunners returns the runners found in the namespaces, or in every namespace when none is given,
+// based on the deployments labeled by the engine.
+func (k *k8s) ListRunners(ctx context.Context, namespaces []string) ([]Runner, error) {
+	s := (&Runner{}).labels()
+	delete(s, "app.kubernetes.io/instance")
+	delete(s, "app.kubernetes.io/version")
+
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	var runners []Runner
+
+	for _, namespace := range namespaces {
+		list, err := k.client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(s).String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list runner deployments: %w", err)
+		}
+
+		for _, d := range list.Items {
+			runners = append(runners, Runner{
+				Flow:      d.Labels["app.kubernetes.io/instance"],
+				Namespace: d.Namespace,
+				Version:   d.Labels["app.kubernetes.io/version"],
+				Created:   d.CreationTimestamp.Time,
+			})
+		}
+	}
+
+	return runners, nil
+}
+
+// SyncRunner creates the missing kubernetes resources of the runner and updates the ones which
+// drifted from the desired state. It returns a description of every performed change.
+func (k *k8s) SyncRunner(ctx context.Context, r Runner) ([]string, error) {
+	var changes []string
+
+	cm := r.configMap()
+	configMaps := k.client.CoreV1().ConfigMaps(r.Namespace)
+
+	c, err := configMaps.Get(ctx, cm.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err = configMaps.Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			return changes, fmt.Errorf("failed to create config map: %w", err)
+		}
+
+		changes = append(changes, fmt.Sprintf("configmap/%s created", cm.Name))
+	case err != nil:
+		return changes, fmt.Errorf("failed to get config map: %w", err)
+	case !equality.Semantic.DeepEqual(cm.Data, c.Data) || !equality.Semantic.DeepDerivative(cm.Labels, c.Labels):
+		c.Data, c.Labels = cm.Data, cm.Labels
+		if _, err = configMaps.Update(ctx, c, metav1.UpdateOptions{}); err != nil {
+			return changes, fmt.Errorf("failed to update config map: %w", err)
+		}
+
+		changes = append(changes, fmt.Sprintf("configmap/%s updated", cm.Name))
+	}
+
+	dp := r.deployment()
+	deployments := k.client.AppsV1().Deployments(r.Namespace)
+
+	d, err := deployments.Get(ctx, dp.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err = deployments.Create(ctx, dp, metav1.CreateOptions{}); err != nil {
+			return changes, fmt.Errorf("failed to create deployment: %w", err)
+		}
+
+		changes = append(changes, fmt.Sprintf("deployment/%s created", dp.Name))
+	case err != nil:
+		return changes, fmt.Errorf("failed to get deployment: %w", err)
+	case !equality.Semantic.DeepDerivative(dp.Spec.Template, d.Spec.Template) || !equality.Semantic.DeepDerivative(dp.Labels, d.Labels):
+		d.Spec.Template, d.Labels = dp.Spec.Template, dp.Labels
+		if _, err = deployments.Update(ctx, d, metav1.UpdateOptions{}); err != nil {
+			return changes, fmt.Errorf("failed to update deployment: %w", err)
+		}
+
+		changes = append(changes, fmt.Sprintf("deployment/%s updated", dp.Name))
+	}
+
+	hp := r.hpa()
+	hpas := k.client.AutoscalingV1().HorizontalPodAutoscalers(r.Namespace)
+
+	h, err := hpas.Get(ctx, hp.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err = hpas.Create(ctx, hp, metav1.CreateOptions{}); err != nil {
+			return changes, fmt.Errorf("failed to create hpa: %w", err)
+		}
+
+		changes = append(changes, fmt.Sprintf("hpa/%s created", hp.Name))
+	case err != nil:
+		return changes, fmt.Errorf("failed to get hpa: %w", err)
+	case !equality.Semantic.DeepDerivative(hp.Spec, h.Spec) || !equality.Semantic.DeepDerivative(hp.Labels, h.Labels):
+		h.Spec, h.Labels = hp.Spec, hp.Labels
+		if _, err = hpas.Update(ctx, h, metav1.UpdateOptions{}); err != nil {
+			return changes, fmt.Errorf("failed to update hpa: %w", err)
+		}
+
+		changes = append(changes, fmt.Sprintf("hpa/%s updated", hp.Name))
+	}
+
+	return changes, nil
+}
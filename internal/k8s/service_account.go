@@ -0,0 +1,280 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	serviceAccountNamespace = "jetbuild"
+	serviceAccountName      = "jetbuild-engine"
+	serviceAccountTimeout   = 30 * time.Second
+)
+
+// CreateServiceAccount provisions a service account granted with the permissions of the engine, and returns
+// a client authenticated with its long-lived token. The permissions are bound in every namespace of the
+// allow-list, or in the whole cluster when it is empty. The returned cleanup removes the objects created by
+// the call, for when the client ends up unused, while the objects which existed already are kept.
+func (k *k8s) CreateServiceAccount(ctx context.Context, namespaces []string) (K8S, func(context.Context) error, error) {
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "jetbuild",
+	}
+
+	meta := metav1.ObjectMeta{
+		Name:      serviceAccountName,
+		Namespace: serviceAccountNamespace,
+		Labels:    labels,
+	}
+
+	var created []func(context.Context) error
+
+	cleanup := func(ctx context.Context) error {
+		var errs []error
+
+		for i := len(created) - 1; i >= 0; i-- {
+			if err := created[i](ctx); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+		}
+
+		if len(errs) > 0 {
+			return fmt.Errorf("failed to remove service account resources: %w", errors.Join(errs...))
+		}
+
+		return nil
+	}
+
+	// whatever was created before a failure is removed again
+	fail := func(err error) (K8S, func(context.Context) error, error) {
+		return nil, nil, errors.Join(err, cleanup(ctx))
+	}
+
+	err := k.CreateNamespace(ctx, serviceAccountNamespace)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fail(fmt.Errorf("failed to create service account namespace: %w", err))
+	}
+	if err == nil {
+		created = append(created, func(ctx context.Context) error {
+			return k.client.CoreV1().Namespaces().Delete(ctx, serviceAccountNamespace, metav1.DeleteOptions{})
+		})
+	}
+
+	_, err = k.client.CoreV1().ServiceAccounts(serviceAccountNamespace).Create(ctx, &corev1.ServiceAccount{
+		ObjectMeta: meta,
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fail(fmt.Errorf("failed to create service account: %w", err))
+	}
+	if err == nil {
+		created = append(created, func(ctx context.Context) error {
+			return k.client.CoreV1().ServiceAccounts(serviceAccountNamespace).Delete(ctx, serviceAccountName, metav1.DeleteOptions{})
+		})
+	}
+
+	ok, err := k.applyClusterRole(ctx, labels)
+	if err != nil {
+		return fail(err)
+	}
+	if ok {
+		created = append(created, func(ctx context.Context) error {
+			return k.client.RbacV1().ClusterRoles().Delete(ctx, serviceAccountName, metav1.DeleteOptions{})
+		})
+	}
+
+	subjects := []rbacv1.Subject{
+		{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      serviceAccountName,
+			Namespace: serviceAccountNamespace,
+		},
+	}
+
+	roleRef := rbacv1.RoleRef{
+		APIGroup: rbacv1.GroupName,
+		Kind:     "ClusterRole",
+		Name:     serviceAccountName,
+	}
+
+	if len(namespaces) == 0 {
+		_, err = k.client.RbacV1().ClusterRoleBindings().Create(ctx, &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   serviceAccountName,
+				Labels: labels,
+			},
+			Subjects: subjects,
+			RoleRef:  roleRef,
+		}, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return fail(fmt.Errorf("failed to create cluster role binding: %w", err))
+		}
+		if err == nil {
+			created = append(created, func(ctx context.Context) error {
+				return k.client.RbacV1().ClusterRoleBindings().Delete(ctx, serviceAccountName, metav1.DeleteOptions{})
+			})
+		}
+	}
+
+	for _, namespace := range namespaces {
+		namespace := namespace
+
+		_, err = k.client.RbacV1().RoleBindings(namespace).Create(ctx, &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      serviceAccountName,
+				Namespace: namespace,
+				Labels:    labels,
+			},
+			Subjects: subjects,
+			RoleRef:  roleRef,
+		}, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return fail(fmt.Errorf("failed to create role binding in namespace '%s': %w", namespace, err))
+		}
+		if err == nil {
+			created = append(created, func(ctx context.Context) error {
+				return k.client.RbacV1().RoleBindings(namespace).Delete(ctx, serviceAccountName, metav1.DeleteOptions{})
+			})
+		}
+	}
+
+	token := meta
+	token.Annotations = map[string]string{
+		corev1.ServiceAccountNameKey: serviceAccountName,
+	}
+
+	_, err = k.client.CoreV1().Secrets(serviceAccountNamespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: token,
+		Type:       corev1.SecretTypeServiceAccountToken,
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fail(fmt.Errorf("failed to create service account token secret: %w", err))
+	}
+	if err == nil {
+		created = append(created, func(ctx context.Context) error {
+			return k.client.CoreV1().Secrets(serviceAccountNamespace).Delete(ctx, serviceAccountName, metav1.DeleteOptions{})
+		})
+	}
+
+	// the token controller populates the secret asynchronously
+	var secret *corev1.Secret
+	err = wait.PollUntilContextTimeout(ctx, time.Second, serviceAccountTimeout, true, func(ctx context.Context) (bool, error) {
+		s, gErr := k.client.CoreV1().Secrets(serviceAccountNamespace).Get(ctx, serviceAccountName, metav1.GetOptions{})
+		if gErr != nil {
+			return false, gErr
+		}
+
+		secret = s
+
+		return len(s.Data[corev1.ServiceAccountTokenKey]) > 0, nil
+	})
+	if err != nil {
+		return fail(fmt.Errorf("failed to wait for service account token: %w", err))
+	}
+
+	c, err := k.newServiceAccountClient(secret)
+	if err != nil {
+		return fail(err)
+	}
+
+	return c, cleanup, nil
+}
+
+// applyClusterRole creates the cluster role of the service account, or replaces its rules when it exists
+// already. It reports whether the role was created.
+func (k *k8s) applyClusterRole(ctx context.Context, labels map[string]string) (bool, error) {
+	var rules []rbacv1.PolicyRule
+	for _, p := range permissions {
+		resource := p.resource
+		if p.subresource != "" {
+			resource += "/" + p.subresource
+		}
+
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{p.group},
+			Resources: []string{resource},
+			Verbs:     p.verbs,
+		})
+	}
+
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   serviceAccountName,
+			Labels: labels,
+		},
+		Rules: rules,
+	}
+
+	_, err := k.client.RbacV1().ClusterRoles().Create(ctx, role, metav1.CreateOptions{})
+	if err == nil {
+		return true, nil
+	}
+
+	if apierrors.IsAlreadyExists(err) {
+		// the rules of a previous engine version are replaced
+		_, err = k.client.RbacV1().ClusterRoles().Update(ctx, role, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to apply cluster role: %w", err)
+	}
+
+	return false, nil
+}
+
+// newServiceAccountClient synthesizes a kube config from the service account token secret, which points
+// to the same api server as the client.
+func (k *k8s) newServiceAccountClient(secret *corev1.Secret) (K8S, error) {
+	ca := secret.Data[corev1.ServiceAccountRootCAKey]
+	if len(ca) == 0 {
+		ca = k.rest.CAData
+	}
+
+	c := clientcmdapi.NewConfig()
+	c.Clusters[k.clusterName] = &clientcmdapi.Cluster{
+		Server:                   k.rest.Host,
+		CertificateAuthorityData: ca,
+		TLSServerName:            k.rest.TLSClientConfig.ServerName,
+		InsecureSkipTLSVerify:    k.rest.Insecure,
+	}
+	c.AuthInfos[serviceAccountName] = &clientcmdapi.AuthInfo{
+		Token: string(secret.Data[corev1.ServiceAccountTokenKey]),
+	}
+	c.Contexts[k.clusterName] = &clientcmdapi.Context{
+		Cluster:   k.clusterName,
+		AuthInfo:  serviceAccountName,
+		Namespace: serviceAccountNamespace,
+	}
+	c.CurrentContext = k.clusterName
+
+	r, err := clientcmd.NewDefaultClientConfig(*c, nil).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new default client config: %w", err)
+	}
+
+	client, err := kubernetes.NewForConfig(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	cfg, err := storedConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &k8s{
+		client:      client,
+		rest:        r,
+		config:      cfg,
+		clusterName: k.clusterName,
+		authMethod:  AuthMethodToken,
+	}, nil
+}
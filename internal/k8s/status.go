@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jetbuild/engine/internal/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const reasonCrashLoopBackOff = "CrashLoopBackOff"
+
+// GetRunnerStatus computes the status of the runner from its deployment, hpa and pods.
+func (k *k8s) GetRunnerStatus(ctx context.Context, r Runner) (*model.RunnerStatus, error) {
+	d, err := k.client.AppsV1().Deployments(r.Namespace).Get(ctx, r.name(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return &model.RunnerStatus{
+			Health:  model.HealthUnhealthy,
+			Message: "runner deployment does not exist",
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	s := model.RunnerStatus{
+		Replicas: model.RunnerReplicas{
+			Desired:   d.Status.Replicas,
+			Ready:     d.Status.ReadyReplicas,
+			Updated:   d.Status.UpdatedReplicas,
+			Available: d.Status.AvailableReplicas,
+		},
+	}
+
+	if d.Spec.Replicas != nil {
+		s.Replicas.Desired = *d.Spec.Replicas
+	}
+
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentAvailable {
+			s.Available = c.Status == corev1.ConditionTrue
+		}
+	}
+
+	h, err := k.client.AutoscalingV1().HorizontalPodAutoscalers(r.Namespace).Get(ctx, r.name(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get hpa: %w", err)
+	}
+
+	if err == nil {
+		s.Autoscaler = &model.RunnerAutoscaler{
+			MaxReplicas:     h.Spec.MaxReplicas,
+			CurrentReplicas: h.Status.CurrentReplicas,
+			DesiredReplicas: h.Status.DesiredReplicas,
+			TargetCPU:       h.Spec.TargetCPUUtilizationPercentage,
+			CurrentCPU:      h.Status.CurrentCPUUtilizationPercentage,
+		}
+
+		if h.Spec.MinReplicas != nil {
+			s.Autoscaler.MinReplicas = *h.Spec.MinReplicas
+		}
+
+		if h.Status.LastScaleTime != nil {
+			s.Autoscaler.LastScaleTime = &h.Status.LastScaleTime.Time
+		}
+	}
+
+	pods, err := k.client.CoreV1().Pods(r.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(r.selector()).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var crashing bool
+
+	for _, p := range pods.Items {
+		pod := model.RunnerPod{
+			Name:  p.Name,
+			Phase: string(p.Status.Phase),
+		}
+
+		for _, c := range p.Status.Conditions {
+			if c.Type == corev1.PodReady {
+				pod.Ready = c.Status == corev1.ConditionTrue
+			}
+		}
+
+		for _, c := range p.Status.ContainerStatuses {
+			pod.Restarts += c.RestartCount
+
+			if w := c.State.Waiting; w != nil && len(w.Reason) > 0 {
+				pod.Reason, pod.Message = w.Reason, w.Message
+				crashing = crashing || w.Reason == reasonCrashLoopBackOff
+			}
+
+			if t := c.LastTerminationState.Terminated; t != nil {
+				pod.LastTerminationReason = t.Reason
+				pod.LastTerminationTime = &t.FinishedAt.Time
+			}
+		}
+
+		s.TotalRestarts += pod.Restarts
+		if t := pod.LastTerminationTime; t != nil && (s.LastRestartTime == nil || t.After(*s.LastRestartTime)) {
+			s.LastRestartTime = t
+		}
+		s.Pods = append(s.Pods, pod)
+	}
+
+	switch {
+	case crashing:
+		s.Health = model.HealthUnhealthy
+		s.Message = "runner pods are in crash loop"
+	case s.Replicas.Ready == 0:
+		s.Health = model.HealthUnhealthy
+		s.Message = "runner does not have a ready replica"
+	case !s.Available || s.Replicas.Ready < s.Replicas.Desired:
+		s.Health = model.HealthDegraded
+		s.Message = "runner does not have every desired replica ready"
+	default:
+		s.Health = model.HealthHealthy
+	}
+
+	return &s, nil
+}
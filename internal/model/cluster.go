@@ -1,6 +1,34 @@
 package model
 
+import "slices"
+
+const (
+	ClusterTypeKubeConfig = "kubeconfig"
+	ClusterTypeInCluster  = "in-cluster"
+)
+
 type Cluster struct {
 	Name   string `json:"name,omitempty"`
 	Config any    `json:"config,omitempty"`
+
+	// Type tells how the client of the cluster is created, where an empty type is a kube config cluster
+	// stored before the types existed.
+	Type string `json:"type,omitempty"`
+
+	// AuthMethod is how the stored kube config authenticates, which is never a secret.
+	AuthMethod string `json:"authMethod,omitempty"`
+
+	// Namespaces is the allow-list of the namespaces the engine may manage, where an empty list means
+	// the engine manages every namespace of the cluster.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	Inventory *ClusterInventory `json:"inventory,omitempty"`
+}
+
+func (c *Cluster) IsInCluster() bool {
+	return c.Type == ClusterTypeInCluster
+}
+
+func (c *Cluster) AllowsNamespace(namespace string) bool {
+	return len(c.Namespaces) == 0 || slices.Contains(c.Namespaces, namespace)
 }
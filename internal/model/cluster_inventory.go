@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// ClusterInventory describes a cluster. Nodes and the allocatable resources are missing when the
+// credentials are not allowed to list the nodes.
+type ClusterInventory struct {
+	ServerVersion     string     `json:"serverVersion,omitempty"`
+	Endpoint          string     `json:"endpoint,omitempty"`
+	Nodes             *int       `json:"nodes,omitempty"`
+	AllocatableCPU    string     `json:"allocatableCpu,omitempty"`
+	AllocatableMemory string     `json:"allocatableMemory,omitempty"`
+	LastContact       *time.Time `json:"lastContact,omitempty"`
+	Reachable         bool       `json:"reachable"`
+}
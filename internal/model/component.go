@@ -18,6 +18,14 @@ type Component struct {
 	Arguments   []ComponentArgument `json:"arguments,omitempty" yaml:"arguments"`
 }
 
+// ComponentError is a component spec which could not be loaded into the catalog. The ref is empty when
+// the releases of the repository could not be listed.
+type ComponentError struct {
+	Repository string `json:"repository"`
+	Ref        string `json:"ref,omitempty"`
+	Error      string `json:"error"`
+}
+
 type ComponentArgument struct {
 	Key         string                `json:"key,omitempty" yaml:"key"`
 	Name        string                `json:"name,omitempty" yaml:"name"`
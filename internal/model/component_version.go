@@ -0,0 +1,217 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+type versionPredicate func(v *version.Version) bool
+
+// ResolveComponent returns the highest version of the component which matches the constraint. The
+// constraint is an exact version, comparisons like '>=1.2.0 <2.0.0', a caret range '^1.2' or a tilde
+// range '~1.2.3'. A partial version like '1.2' matches every version it prefixes. An empty constraint
+// matches the latest release, and pre-releases only match exactly.
+func ResolveComponent(components []Component, key, constraint string) (*Component, error) {
+	var candidates []Component
+	for _, c := range components {
+		if c.Key == key {
+			candidates = append(candidates, c)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("component '%s' does not found", key)
+	}
+
+	exact := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(constraint), "="), "v")
+	for _, c := range candidates {
+		if c.Version == exact {
+			return &c, nil
+		}
+	}
+
+	predicates, err := parseConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	SortComponentVersions(candidates)
+
+	for _, c := range candidates {
+		v, pErr := parseVersion(c.Version)
+		if pErr != nil || len(v.PreRelease()) > 0 {
+			continue
+		}
+
+		if matches(v, predicates) {
+			return &c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("component '%s' does not have a version matching '%s'", key, constraint)
+}
+
+// SortComponentVersions sorts the components from the highest version to the lowest. Versions which are
+// not semantic are sorted after the others, in reverse lexical order.
+func SortComponentVersions(components []Component) {
+	sort.SliceStable(components, func(i, j int) bool {
+		a, aErr := parseVersion(components[i].Version)
+		b, bErr := parseVersion(components[j].Version)
+
+		switch {
+		case aErr == nil && bErr == nil:
+			return b.LessThan(a)
+		case aErr == nil:
+			return true
+		case bErr == nil:
+			return false
+		default:
+			return components[i].Version > components[j].Version
+		}
+	})
+}
+
+// parseVersion parses a semantic version, allowing the 'v' prefix and a missing minor or patch.
+func parseVersion(s string) (*version.Version, error) {
+	v, _, err := parsePartialVersion(s)
+
+	return v, err
+}
+
+// parsePartialVersion parses the version like parseVersion, and also returns how many of its major, minor
+// and patch parts are given, since a missing part matches any value in a constraint.
+func parsePartialVersion(s string) (*version.Version, int, error) {
+	s = strings.TrimPrefix(s, "v")
+
+	core, rest, _ := strings.Cut(s, "-")
+
+	parts := strings.Count(core, ".") + 1
+	for i := parts; i < 3; i++ {
+		core += ".0"
+	}
+
+	if len(rest) > 0 {
+		core += "-" + rest
+	}
+
+	v, err := version.ParseSemantic(core)
+
+	return v, parts, err
+}
+
+func parseConstraint(constraint string) ([]versionPredicate, error) {
+	var predicates []versionPredicate
+
+	for _, term := range strings.FieldsFunc(constraint, func(r rune) bool {
+		return r == ' ' || r == ','
+	}) {
+		i := strings.IndexFunc(term, func(r rune) bool {
+			return !strings.ContainsRune("<>=!^~", r)
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("version constraint '%s' does not have a version", term)
+		}
+
+		op := term[:i]
+
+		v, parts, err := parsePartialVersion(term[i:])
+		if err != nil {
+			return nil, fmt.Errorf("version constraint '%s' is not valid: %w", term, err)
+		}
+
+		// a partial version stands for every version it prefixes, which are below next
+		next := nextVersion(v, parts)
+		partial := parts < 3
+
+		switch op {
+		case "", "=":
+			predicates = append(predicates, func(c *version.Version) bool {
+				if partial {
+					return c.AtLeast(v) && c.LessThan(next)
+				}
+
+				return !c.LessThan(v) && !v.LessThan(c)
+			})
+		case "!=":
+			predicates = append(predicates, func(c *version.Version) bool {
+				if partial {
+					return c.LessThan(v) || c.AtLeast(next)
+				}
+
+				return c.LessThan(v) || v.LessThan(c)
+			})
+		case ">":
+			predicates = append(predicates, func(c *version.Version) bool {
+				if partial {
+					return c.AtLeast(next)
+				}
+
+				return v.LessThan(c)
+			})
+		case ">=":
+			predicates = append(predicates, func(c *version.Version) bool { return c.AtLeast(v) })
+		case "<":
+			predicates = append(predicates, func(c *version.Version) bool { return c.LessThan(v) })
+		case "<=":
+			predicates = append(predicates, func(c *version.Version) bool {
+				if partial {
+					return c.LessThan(next)
+				}
+
+				return !v.LessThan(c)
+			})
+		case "^", "~":
+			upper := caretUpper(v, parts)
+			if op == "~" {
+				upper = nextVersion(v, min(parts, 2))
+			}
+
+			predicates = append(predicates, func(c *version.Version) bool {
+				return c.AtLeast(v) && c.LessThan(upper)
+			})
+		default:
+			return nil, fmt.Errorf("version constraint '%s' has unknown operator '%s'", term, op)
+		}
+	}
+
+	return predicates, nil
+}
+
+// nextVersion returns the lowest version above every version which starts with the given parts of v.
+func nextVersion(v *version.Version, parts int) *version.Version {
+	switch parts {
+	case 1:
+		return version.MustParseSemantic(fmt.Sprintf("%d.0.0", v.Major()+1))
+	case 2:
+		return version.MustParseSemantic(fmt.Sprintf("%d.%d.0", v.Major(), v.Minor()+1))
+	default:
+		return version.MustParseSemantic(fmt.Sprintf("%d.%d.%d", v.Major(), v.Minor(), v.Patch()+1))
+	}
+}
+
+// caretUpper returns the exclusive upper bound of a caret range, which allows the changes that do not
+// modify the left-most non-zero part of the version. A missing part is never the left-most non-zero one,
+// so '^0' allows every 0.x.y version and '^0.0' every 0.0.y version.
+func caretUpper(v *version.Version, parts int) *version.Version {
+	switch {
+	case v.Major() > 0 || parts == 1:
+		return nextVersion(v, 1)
+	case v.Minor() > 0 || parts == 2:
+		return nextVersion(v, 2)
+	default:
+		return nextVersion(v, 3)
+	}
+}
+
+func matches(v *version.Version, predicates []versionPredicate) bool {
+	for _, p := range predicates {
+		if !p(v) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,124 @@
+package model_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jetbuild/engine/internal/model"
+)
+
+func components(key string, versions ...string) []model.Component {
+	var components []model.Component
+	for _, v := range versions {
+		components = append(components, model.Component{Key: key, Version: v})
+	}
+
+	return components
+}
+
+func TestResolveComponent(t *testing.T) {
+	available := append(
+		components("http", "0.0.1", "0.0.3", "0.1.0", "0.2.5", "1.0.0", "1.2.0", "1.2.9", "1.3.0", "1.10.0", "2.0.0-rc.1", "2.0.0"),
+		components("cron", "main")...,
+	)
+
+	tests := []struct {
+		name       string
+		key        string
+		constraint string
+		want       string
+		wantErr    bool
+	}{
+		{name: "latest", key: "http", constraint: "", want: "2.0.0"},
+		{name: "exact", key: "http", constraint: "1.2.0", want: "1.2.0"},
+		{name: "exact with prefix", key: "http", constraint: "v1.2.9", want: "1.2.9"},
+		{name: "exact pre-release", key: "http", constraint: "2.0.0-rc.1", want: "2.0.0-rc.1"},
+		{name: "exact not semantic", key: "cron", constraint: "main", want: "main"},
+		{name: "partial major", key: "http", constraint: "1", want: "1.10.0"},
+		{name: "partial minor", key: "http", constraint: "1.2", want: "1.2.9"},
+		{name: "caret", key: "http", constraint: "^1.2", want: "1.10.0"},
+		{name: "caret zero major", key: "http", constraint: "^0.2", want: "0.2.5"},
+		{name: "caret zero", key: "http", constraint: "^0", want: "0.2.5"},
+		{name: "caret zero minor", key: "http", constraint: "^0.0", want: "0.0.3"},
+		{name: "caret zero patch", key: "http", constraint: "^0.0.1", want: "0.0.1"},
+		{name: "tilde major", key: "http", constraint: "~1", want: "1.10.0"},
+		{name: "tilde minor", key: "http", constraint: "~1.2", want: "1.2.9"},
+		{name: "tilde patch", key: "http", constraint: "~1.2.3", want: "1.2.9"},
+		{name: "range", key: "http", constraint: ">=1.0.0 <1.3.0", want: "1.2.9"},
+		{name: "range with comma", key: "http", constraint: ">=1.0.0, <1.3.0", want: "1.2.9"},
+		{name: "greater than partial", key: "http", constraint: ">1", want: "2.0.0"},
+		{name: "at most partial", key: "http", constraint: "<=1.2", want: "1.2.9"},
+		{name: "not equal partial", key: "http", constraint: ">=1.0.0 <2.0.0 !=1.10", want: "1.3.0"},
+		{name: "pre-release not in range", key: "http", constraint: ">=2.0.0-rc.0 <2.0.0", wantErr: true},
+		{name: "no match", key: "http", constraint: "^3", wantErr: true},
+		{name: "unknown operator", key: "http", constraint: "=>1.0.0", wantErr: true},
+		{name: "invalid version", key: "http", constraint: "^x", wantErr: true},
+		{name: "unknown component", key: "grpc", constraint: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := model.ResolveComponent(available, tt.key, tt.constraint)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got version '%s'", got.Version)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Version != tt.want {
+				t.Errorf("got version '%s', want '%s'", got.Version, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortComponentVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		want     []string
+	}{
+		{
+			name:     "semantic",
+			versions: []string{"1.2.0", "1.10.0", "0.9.1", "1.2.10", "1.2.9"},
+			want:     []string{"1.10.0", "1.2.10", "1.2.9", "1.2.0", "0.9.1"},
+		},
+		{
+			name:     "pre-release",
+			versions: []string{"2.0.0-rc.1", "1.0.0", "2.0.0", "2.0.0-rc.2"},
+			want:     []string{"2.0.0", "2.0.0-rc.2", "2.0.0-rc.1", "1.0.0"},
+		},
+		{
+			name:     "prefix and partial",
+			versions: []string{"v1.1", "1.0.5", "v2"},
+			want:     []string{"v2", "v1.1", "1.0.5"},
+		},
+		{
+			name:     "not semantic last",
+			versions: []string{"develop", "1.0.0", "main", "0.1.0"},
+			want:     []string{"1.0.0", "0.1.0", "main", "develop"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := components("http", tt.versions...)
+
+			model.SortComponentVersions(c)
+
+			var got []string
+			for _, component := range c {
+				got = append(got, component.Version)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package model
+
+import "github.com/jetbuild/engine/pkg/flow"
+
+type Flow struct {
+	flow.Flow
+	Health  Health       `json:"health,omitempty"`
+	Runners []FlowRunner `json:"runners,omitempty"`
+}
+
+type FlowRunner struct {
+	flow.Runner
+	Status *RunnerStatus `json:"status,omitempty"`
+}
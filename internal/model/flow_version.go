@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type FlowVersion struct {
+	Version   int64     `json:"version,omitempty"`
+	Created   time.Time `json:"created"`
+	Author    string    `json:"author,omitempty"`
+	Deleted   bool      `json:"deleted"`
+	Destroyed bool      `json:"destroyed"`
+}
@@ -0,0 +1,14 @@
+package model
+
+// Permission is the review of a permission the engine needs. An optional permission which is denied only
+// disables the feature it is used for, so it does not fail the check.
+type Permission struct {
+	Namespace   string `json:"namespace,omitempty"`
+	Group       string `json:"group,omitempty"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Verb        string `json:"verb"`
+	Allowed     bool   `json:"allowed"`
+	Optional    bool   `json:"optional,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
@@ -3,9 +3,13 @@ package model
 import (
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"k8s.io/apimachinery/pkg/util/version"
 )
 
 type AddClusterNamespaceRequest struct {
@@ -61,9 +65,11 @@ type AddFlowRequest struct {
 	Components []AddFlowRequestComponent `json:"components" validate:"min=1,dive"`
 }
 
+// AddFlowRequestComponent requests a component by an exact version or a version constraint, which is
+// replaced by the resolved version on validation.
 type AddFlowRequestComponent struct {
 	Key         string                            `json:"key" validate:"required"`
-	Version     string                            `json:"-"`
+	Version     string                            `json:"version"`
 	Arguments   map[string]any                    `json:"arguments"`
 	Connections AddFlowRequestComponentConnection `json:"connections"`
 }
@@ -95,18 +101,15 @@ func (r *AddFlowRequest) Validate(components []Component) error {
 			isTrigger = true
 		}
 
-		var component *Component
-
-		for _, cp := range components {
-			if c.Key == cp.Key {
-				component = &cp
-
-				break
-			}
+		if !slices.ContainsFunc(components, func(cp Component) bool {
+			return c.Key == cp.Key
+		}) {
+			return fmt.Errorf("components[%d].key '%s' does not found", i, c.Key)
 		}
 
-		if component == nil {
-			return fmt.Errorf("components[%d].key '%s' does not found", i, c.Key)
+		component, err := ResolveComponent(components, c.Key, c.Version)
+		if err != nil {
+			return fmt.Errorf("components[%d].version: %w", i, err)
 		}
 
 		r.Components[i].Version = component.Version
@@ -180,8 +183,10 @@ func (r *AddFlowRequest) Validate(components []Component) error {
 
 type AddFlowRunnerRequest struct {
 	Body struct {
-		Cluster   string `json:"cluster" validate:"required"`
-		Namespace string `json:"namespace" validate:"required"`
+		Cluster     string `json:"cluster" validate:"required"`
+		Namespace   string `json:"namespace" validate:"required"`
+		MinReplicas int32  `json:"minReplicas" validate:"omitempty,min=1"`
+		MaxReplicas int32  `json:"maxReplicas" validate:"omitempty,min=1,gtefield=MinReplicas"`
 	}
 
 	Params struct {
@@ -208,3 +213,491 @@ func (r *AddFlowRunnerRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error
 
 	return nil
 }
+
+type ListFlowVersionsRequest struct {
+	Params struct {
+		FlowName string `params:"name" validate:"required"`
+	}
+}
+
+func (r *ListFlowVersionsRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type GetFlowVersionRequest struct {
+	Params struct {
+		FlowName string `params:"name" validate:"required"`
+		Version  int64  `params:"version" validate:"required,min=1"`
+	}
+}
+
+func (r *GetFlowVersionRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to parse request params: %s", err))
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type RollbackFlowRequest struct {
+	Body struct {
+		Version int64 `json:"version" validate:"required,min=1"`
+	}
+
+	Params struct {
+		FlowName string `params:"name" validate:"required"`
+	}
+}
+
+func (r *RollbackFlowRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.BodyParser(&r.Body); err != nil {
+		return fmt.Errorf("failed to parse request body: %w", err)
+	}
+
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type GetClusterRequest struct {
+	Params struct {
+		ClusterName string `params:"name" validate:"required"`
+	}
+}
+
+func (r *GetClusterRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type RefreshClusterRequest struct {
+	Params struct {
+		ClusterName string `params:"name" validate:"required"`
+	}
+}
+
+func (r *RefreshClusterRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type AddClusterRequest struct {
+	Body struct {
+		Type       string   `json:"type" form:"type" validate:"omitempty,oneof=kubeconfig in-cluster"`
+		Name       string   `json:"name" form:"name" validate:"required_if=Type in-cluster"`
+		Context    string   `json:"-" form:"context"`
+		Namespaces []string `json:"namespaces" form:"namespaces" validate:"dive,hostname_rfc1123"`
+
+		// ServiceAccount uses the kube config only once, to create a dedicated service account whose
+		// kube config is stored instead.
+		ServiceAccount bool `json:"-" form:"serviceAccount"`
+	}
+}
+
+func (r *AddClusterRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.BodyParser(&r.Body); err != nil {
+		return fmt.Errorf("failed to parse request body: %w", err)
+	}
+
+	if err := v.Struct(r.Body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type UpdateClusterKubeConfigRequest struct {
+	Body struct {
+		Context    string   `form:"context"`
+		Namespaces []string `form:"namespaces" validate:"dive,hostname_rfc1123"`
+	}
+
+	Params struct {
+		ClusterName string `params:"name" validate:"required"`
+	}
+}
+
+func (r *UpdateClusterKubeConfigRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.BodyParser(&r.Body); err != nil {
+		return fmt.Errorf("failed to parse request body: %w", err)
+	}
+
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type RemoveClusterRequest struct {
+	Params struct {
+		ClusterName string `params:"name" validate:"required"`
+	}
+
+	Query struct {
+		Force bool `query:"force"`
+	}
+}
+
+func (r *RemoveClusterRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := ctx.QueryParser(&r.Query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to parse request query: %s", err))
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type GetFlowRequest struct {
+	Params struct {
+		FlowName string `params:"name" validate:"required"`
+	}
+}
+
+func (r *GetFlowRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type UpdateFlowRequest struct {
+	Body AddFlowRequest
+
+	Params struct {
+		FlowName string `params:"name" validate:"required"`
+	}
+}
+
+func (r *UpdateFlowRequest) Bind(ctx *fiber.Ctx, v *validator.Validate, components []Component) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := ctx.BodyParser(&r.Body); err != nil {
+		return fmt.Errorf("failed to parse request body: %w", err)
+	}
+
+	if len(r.Body.Name) == 0 {
+		r.Body.Name = r.Params.FlowName
+	}
+
+	if r.Body.Name != r.Params.FlowName {
+		return fiber.NewError(fiber.StatusBadRequest, "flow name cannot be changed")
+	}
+
+	if err := v.Struct(r.Body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := r.Body.Validate(components); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type PatchFlowRequest struct {
+	Params struct {
+		FlowName string `params:"name" validate:"required"`
+	}
+}
+
+func (r *PatchFlowRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if !strings.HasPrefix(ctx.Get(fiber.HeaderContentType), "application/merge-patch+json") {
+		return fiber.NewError(fiber.StatusUnsupportedMediaType, "request body should be a json merge patch")
+	}
+
+	return nil
+}
+
+type RemoveFlowRequest struct {
+	Params struct {
+		FlowName string `params:"name" validate:"required"`
+	}
+}
+
+func (r *RemoveFlowRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type ListFlowRunnersRequest struct {
+	Params struct {
+		FlowName string `params:"name" validate:"required"`
+	}
+}
+
+func (r *ListFlowRunnersRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type GetFlowRunnerRequest struct {
+	Params struct {
+		FlowName    string `params:"name" validate:"required"`
+		ClusterName string `params:"cluster" validate:"required"`
+	}
+}
+
+func (r *GetFlowRunnerRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type RemoveFlowRunnerRequest struct {
+	Params struct {
+		FlowName    string `params:"name" validate:"required"`
+		ClusterName string `params:"cluster" validate:"required"`
+	}
+}
+
+func (r *RemoveFlowRunnerRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type UpdateFlowRunnerRequest struct {
+	Body struct {
+		Version     string `json:"version"`
+		MinReplicas *int32 `json:"minReplicas" validate:"omitempty,min=1"`
+		MaxReplicas *int32 `json:"maxReplicas" validate:"omitempty,min=1"`
+	}
+
+	Params struct {
+		FlowName    string `params:"name" validate:"required"`
+		ClusterName string `params:"cluster" validate:"required"`
+	}
+}
+
+func (r *UpdateFlowRunnerRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.BodyParser(&r.Body); err != nil {
+		return fmt.Errorf("failed to parse request body: %w", err)
+	}
+
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if len(r.Body.Version) == 0 && r.Body.MinReplicas == nil && r.Body.MaxReplicas == nil {
+		return fiber.NewError(fiber.StatusBadRequest, "at least one of 'version', 'minReplicas' or 'maxReplicas' is required")
+	}
+
+	if len(r.Body.Version) > 0 && r.Body.Version != "latest" {
+		if _, err := version.ParseSemantic(strings.TrimLeft(r.Body.Version, "v")); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("version '%s' is not a semantic version", r.Body.Version))
+		}
+	}
+
+	return nil
+}
+
+type ListFlowsRequest struct {
+	Query struct {
+		Status bool `query:"status"`
+	}
+}
+
+func (r *ListFlowsRequest) Bind(ctx *fiber.Ctx) error {
+	if err := ctx.QueryParser(&r.Query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to parse request query: %s", err))
+	}
+
+	return nil
+}
+
+type StreamFlowRunnerLogsRequest struct {
+	Params struct {
+		FlowName    string `params:"name" validate:"required"`
+		ClusterName string `params:"cluster" validate:"required"`
+	}
+
+	Query struct {
+		Pod        string `query:"pod"`
+		Container  string `query:"container"`
+		Follow     bool   `query:"follow"`
+		Since      string `query:"since"`
+		Tail       *int64 `query:"tail" validate:"omitempty,min=0"`
+		Timestamps bool   `query:"timestamps"`
+	}
+
+	Since time.Duration
+}
+
+func (r *StreamFlowRunnerLogsRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := ctx.QueryParser(&r.Query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to parse request query: %s", err))
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := v.Struct(r.Query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if len(r.Query.Since) > 0 {
+		d, err := time.ParseDuration(r.Query.Since)
+		if err != nil || d < 0 {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("since '%s' is not a valid duration", r.Query.Since))
+		}
+
+		r.Since = d
+	}
+
+	return nil
+}
+
+type ListFlowRunnerEventsRequest struct {
+	Params struct {
+		FlowName    string `params:"name" validate:"required"`
+		ClusterName string `params:"cluster" validate:"required"`
+	}
+
+	Query struct {
+		Watch bool `query:"watch"`
+	}
+}
+
+func (r *ListFlowRunnerEventsRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := ctx.QueryParser(&r.Query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("failed to parse request query: %s", err))
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+type ListComponentVersionsRequest struct {
+	Params struct {
+		Key string `params:"key" validate:"required"`
+	}
+}
+
+func (r *ListComponentVersionsRequest) Bind(ctx *fiber.Ctx, v *validator.Validate) error {
+	if err := ctx.ParamsParser(&r.Params); err != nil {
+		return fmt.Errorf("failed to parse request params: %w", err)
+	}
+
+	if err := v.Struct(r.Params); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
@@ -1,19 +1,45 @@
 package model
 
-import "github.com/jetbuild/engine/pkg/flow"
-
 type ListClustersResponse struct {
 	Items []Cluster `json:"items"`
 }
 
+type CheckClusterPermissionsResponse struct {
+	Name        string       `json:"name"`
+	AuthMethod  string       `json:"authMethod"`
+	Namespaces  []string     `json:"namespaces,omitempty"`
+	Permissions []Permission `json:"permissions"`
+}
+
 type ListClusterNamespacesResponse struct {
 	Items []ClusterNamespace `json:"items"`
 }
 
-type ListComponentsResponse struct {
+type ListComponentVersionsResponse struct {
 	Items []Component `json:"items"`
 }
 
+type ListComponentsResponse struct {
+	Items  []Component      `json:"items"`
+	Errors []ComponentError `json:"errors,omitempty"`
+}
+
 type ListFlowsResponse struct {
-	Items []flow.Flow `json:"items"`
+	Items []Flow `json:"items"`
+}
+
+type ListFlowVersionsResponse struct {
+	Items []FlowVersion `json:"items"`
+}
+
+type RewrapClustersResponse struct {
+	Items []string `json:"items"`
+}
+
+type ListFlowRunnersResponse struct {
+	Items []FlowRunner `json:"items"`
+}
+
+type ListRunnerEventsResponse struct {
+	Items []RunnerEvent `json:"items"`
 }
@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+const (
+	HealthHealthy   Health = "healthy"
+	HealthDegraded  Health = "degraded"
+	HealthUnhealthy Health = "unhealthy"
+	HealthUnknown   Health = "unknown"
+)
+
+// Health of a group is the worst health of its members, see Worse.
+type Health string
+
+type RunnerStatus struct {
+	Health     Health            `json:"health"`
+	Message    string            `json:"message,omitempty"`
+	Available  bool              `json:"available"`
+	Replicas   RunnerReplicas    `json:"replicas"`
+	Autoscaler *RunnerAutoscaler `json:"autoscaler,omitempty"`
+	Pods       []RunnerPod       `json:"pods,omitempty"`
+
+	// TotalRestarts counts the container restarts of the current pods since they were created, and
+	// LastRestartTime tells when the latest of them happened.
+	TotalRestarts   int32      `json:"totalRestarts"`
+	LastRestartTime *time.Time `json:"lastRestartTime,omitempty"`
+}
+
+type RunnerReplicas struct {
+	Desired   int32 `json:"desired"`
+	Ready     int32 `json:"ready"`
+	Updated   int32 `json:"updated"`
+	Available int32 `json:"available"`
+}
+
+type RunnerAutoscaler struct {
+	MinReplicas     int32      `json:"minReplicas"`
+	MaxReplicas     int32      `json:"maxReplicas"`
+	CurrentReplicas int32      `json:"currentReplicas"`
+	DesiredReplicas int32      `json:"desiredReplicas"`
+	TargetCPU       *int32     `json:"targetCPU,omitempty"`
+	CurrentCPU      *int32     `json:"currentCPU,omitempty"`
+	LastScaleTime   *time.Time `json:"lastScaleTime,omitempty"`
+}
+
+type RunnerPod struct {
+	Name                  string     `json:"name"`
+	Phase                 string     `json:"phase"`
+	Ready                 bool       `json:"ready"`
+	Restarts              int32      `json:"restarts"`
+	Reason                string     `json:"reason,omitempty"`
+	Message               string     `json:"message,omitempty"`
+	LastTerminationReason string     `json:"lastTerminationReason,omitempty"`
+	LastTerminationTime   *time.Time `json:"lastTerminationTime,omitempty"`
+}
+
+func (h Health) rank() int {
+	switch h {
+	case HealthHealthy:
+		return 0
+	case HealthDegraded:
+		return 1
+	case HealthUnhealthy:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// Worse returns the worse of the two healths, where unknown is only better than unhealthy.
+func (h Health) Worse(o Health) Health {
+	if o.rank() > h.rank() {
+		return o
+	}
+
+	return h
+}
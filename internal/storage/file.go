@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type file[T any] struct {
+	*memory[T]
+	path string
+}
+
+// NewFile returns a storage which keeps the items of the key, with their versions, in a single JSON
+// file under the directory. The file is loaded once and rewritten atomically after every write, so it
+// must not be shared by more than one process.
+func NewFile[T any](dir, key string) (Storage[T], error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	f := &file[T]{
+		memory: &memory[T]{
+			items: make(map[string]*record),
+		},
+		path: filepath.Join(dir, fmt.Sprintf("%s.json", key)),
+	}
+
+	b, err := os.ReadFile(f.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read storage file: %w", err)
+	}
+
+	if len(b) > 0 {
+		if err = json.Unmarshal(b, &f.items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal storage file: %w", err)
+		}
+	}
+
+	f.persist = f.write
+
+	return f, nil
+}
+
+func (f *file[T]) Ping(_ context.Context) error {
+	if _, err := os.Stat(filepath.Dir(f.path)); err != nil {
+		return fmt.Errorf("failed to stat storage directory: %w", err)
+	}
+
+	return nil
+}
+
+func (f *file[T]) write(items map[string]*record) error {
+	b, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal storage file: %w", err)
+	}
+
+	t, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary storage file: %w", err)
+	}
+	defer os.Remove(t.Name())
+
+	if _, err = t.Write(b); err != nil {
+		_ = t.Close()
+
+		return fmt.Errorf("failed to write temporary storage file: %w", err)
+	}
+
+	if err = t.Sync(); err != nil {
+		_ = t.Close()
+
+		return fmt.Errorf("failed to sync temporary storage file: %w", err)
+	}
+
+	if err = t.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary storage file: %w", err)
+	}
+
+	if err = os.Rename(t.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to replace storage file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type memory[T any] struct {
+	mu    sync.RWMutex
+	items map[string]*record
+
+	// persist is called with the lock held after every successful write.
+	persist func(items map[string]*record) error
+}
+
+type record struct {
+	Versions []entry `json:"versions"`
+}
+
+type entry struct {
+	Data    json.RawMessage `json:"data"`
+	Created time.Time       `json:"created"`
+	Author  string          `json:"author,omitempty"`
+}
+
+func NewMemory[T any]() Storage[T] {
+	return &memory[T]{
+		items: make(map[string]*record),
+	}
+}
+
+func (m *memory[T]) Add(ctx context.Context, name string, model T) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.items[name]; ok {
+		return ErrItemAlreadyExist
+	}
+
+	r := &record{}
+	if err := r.append(model, Author(ctx)); err != nil {
+		return err
+	}
+
+	m.items[name] = r
+
+	return m.save(func() {
+		delete(m.items, name)
+	})
+}
+
+func (m *memory[T]) Get(_ context.Context, name string) (*T, int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.items[name]
+	if !ok {
+		return nil, 0, ErrKeyNotFound
+	}
+
+	version := int64(len(r.Versions))
+
+	item, err := decode[T](r.Versions[version-1].Data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return item, version, nil
+}
+
+func (m *memory[T]) List(_ context.Context) (map[string]T, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.items) == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	items := make(map[string]T, len(m.items))
+
+	for name, r := range m.items {
+		item, err := decode[T](r.Versions[len(r.Versions)-1].Data)
+		if err != nil {
+			return nil, err
+		}
+
+		items[name] = *item
+	}
+
+	return items, nil
+}
+
+func (m *memory[T]) Remove(_ context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.items[name]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	delete(m.items, name)
+
+	return m.save(func() {
+		m.items[name] = r
+	})
+}
+
+func (m *memory[T]) Update(ctx context.Context, name string, model T, version int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.items[name]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	if int64(len(r.Versions)) != version {
+		return ErrConflict
+	}
+
+	if err := r.append(model, Author(ctx)); err != nil {
+		return err
+	}
+
+	return m.save(func() {
+		r.Versions = r.Versions[:version]
+	})
+}
+
+func (m *memory[T]) GetVersion(_ context.Context, name string, version int64) (*T, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.items[name]
+	if !ok || version < 1 || version > int64(len(r.Versions)) {
+		return nil, ErrKeyNotFound
+	}
+
+	return decode[T](r.Versions[version-1].Data)
+}
+
+func (m *memory[T]) ListVersions(_ context.Context, name string) ([]Version, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.items[name]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	versions := make([]Version, 0, len(r.Versions))
+	for i, e := range r.Versions {
+		versions = append(versions, Version{
+			Version: int64(i + 1),
+			Created: e.Created,
+			Author:  e.Author,
+		})
+	}
+
+	return versions, nil
+}
+
+func (m *memory[T]) Ping(_ context.Context) error {
+	return nil
+}
+
+// save persists the items, and calls undo to revert the in-memory change when it fails.
+func (m *memory[T]) save(undo func()) error {
+	if m.persist == nil {
+		return nil
+	}
+
+	if err := m.persist(m.items); err != nil {
+		undo()
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *record) append(model any, author string) error {
+	d, err := json.Marshal(model)
+	if err != nil {
+		return fmt.Errorf("failed to marshal item: %w", err)
+	}
+
+	r.Versions = append(r.Versions, entry{
+		Data:    d,
+		Created: time.Now().UTC(),
+		Author:  author,
+	})
+
+	return nil
+}
+
+func decode[T any](data []byte) (*T, error) {
+	var item T
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal item: %w", err)
+	}
+
+	return &item, nil
+}
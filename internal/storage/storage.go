@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	BackendVault  = "vault"
+	BackendFile   = "file"
+	BackendMemory = "memory"
+)
+
+var (
+	ErrItemAlreadyExist = errors.New("item already exist")
+	ErrKeyNotFound      = errors.New("key not found")
+	ErrConflict         = errors.New("item version conflict")
+)
+
+// Storage keeps the items of a single key, where every write of an item creates a new version of it.
+type Storage[T any] interface {
+	Add(ctx context.Context, name string, model T) error
+	Get(ctx context.Context, name string) (*T, int64, error)
+	List(ctx context.Context) (map[string]T, error)
+	Remove(ctx context.Context, name string) error
+	Update(ctx context.Context, name string, model T, version int64) error
+	GetVersion(ctx context.Context, name string, version int64) (*T, error)
+	ListVersions(ctx context.Context, name string) ([]Version, error)
+	Ping(ctx context.Context) error
+}
+
+type Version struct {
+	Version   int64
+	Created   time.Time
+	Author    string
+	Deleted   bool
+	Destroyed bool
+}
+
+type authorKey struct{}
+
+// WithAuthor returns a context whose writes are recorded as made by the author.
+func WithAuthor(ctx context.Context, author string) context.Context {
+	return context.WithValue(ctx, authorKey{}, author)
+}
+
+// Author returns the author of the writes made with the context, which is empty when it is unknown.
+func Author(ctx context.Context) string {
+	a, _ := ctx.Value(authorKey{}).(string)
+
+	return a
+}
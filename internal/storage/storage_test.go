@@ -0,0 +1,54 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jetbuild/engine/internal/storage"
+	"github.com/jetbuild/engine/internal/storage/storagetest"
+)
+
+func TestMemory(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage[storagetest.Item] {
+		return storage.NewMemory[storagetest.Item]()
+	})
+}
+
+func TestFile(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage[storagetest.Item] {
+		s, err := storage.NewFile[storagetest.Item](t.TempDir(), "items")
+		if err != nil {
+			t.Fatalf("new file storage: %v", err)
+		}
+
+		return s
+	})
+}
+
+func TestFileReload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	s, err := storage.NewFile[storagetest.Item](dir, "items")
+	if err != nil {
+		t.Fatalf("new file storage: %v", err)
+	}
+
+	if err = s.Add(ctx, "a", storagetest.Item{Value: "1"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	s, err = storage.NewFile[storagetest.Item](dir, "items")
+	if err != nil {
+		t.Fatalf("reload file storage: %v", err)
+	}
+
+	item, _, err := s.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if item.Value != "1" {
+		t.Errorf("value = %q, want %q", item.Value, "1")
+	}
+}
@@ -0,0 +1,250 @@
+// Package storagetest is the contract test suite which every storage backend must pass.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+type Item struct {
+	Value string `json:"value"`
+}
+
+// Run runs the suite, where every case gets an empty storage from the function.
+func Run(t *testing.T, newStorage func(t *testing.T) storage.Storage[Item]) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, ctx context.Context, s storage.Storage[Item])
+	}{
+		{name: "add and get", test: testAddAndGet},
+		{name: "add existing item", test: testAddExisting},
+		{name: "get missing item", test: testGetMissing},
+		{name: "update", test: testUpdate},
+		{name: "update with stale version", test: testUpdateConflict},
+		{name: "update missing item", test: testUpdateMissing},
+		{name: "list", test: testList},
+		{name: "list empty", test: testListEmpty},
+		{name: "remove", test: testRemove},
+		{name: "remove missing item", test: testRemoveMissing},
+		{name: "versions", test: testVersions},
+		{name: "name with slash", test: testNameWithSlash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, context.Background(), newStorage(t))
+		})
+	}
+}
+
+func testAddAndGet(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	mustAdd(t, ctx, s, "a", "1")
+
+	item, version, err := s.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if item.Value != "1" {
+		t.Errorf("value = %q, want %q", item.Value, "1")
+	}
+
+	if version < 1 {
+		t.Errorf("version = %d, want at least 1", version)
+	}
+}
+
+func testAddExisting(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	mustAdd(t, ctx, s, "a", "1")
+
+	if err := s.Add(ctx, "a", Item{Value: "2"}); !errors.Is(err, storage.ErrItemAlreadyExist) {
+		t.Fatalf("add = %v, want %v", err, storage.ErrItemAlreadyExist)
+	}
+
+	mustValue(t, ctx, s, "a", "1")
+}
+
+func testGetMissing(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	if _, _, err := s.Get(ctx, "missing"); !errors.Is(err, storage.ErrKeyNotFound) {
+		t.Fatalf("get = %v, want %v", err, storage.ErrKeyNotFound)
+	}
+}
+
+func testUpdate(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	mustAdd(t, ctx, s, "a", "1")
+
+	_, version, err := s.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if err = s.Update(ctx, "a", Item{Value: "2"}, version); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	item, next, err := s.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if item.Value != "2" {
+		t.Errorf("value = %q, want %q", item.Value, "2")
+	}
+
+	if next <= version {
+		t.Errorf("version = %d, want greater than %d", next, version)
+	}
+}
+
+func testUpdateConflict(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	mustAdd(t, ctx, s, "a", "1")
+
+	_, version, err := s.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if err = s.Update(ctx, "a", Item{Value: "2"}, version); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if err = s.Update(ctx, "a", Item{Value: "3"}, version); !errors.Is(err, storage.ErrConflict) {
+		t.Fatalf("update = %v, want %v", err, storage.ErrConflict)
+	}
+
+	mustValue(t, ctx, s, "a", "2")
+}
+
+func testUpdateMissing(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	if err := s.Update(ctx, "missing", Item{Value: "1"}, 1); !errors.Is(err, storage.ErrKeyNotFound) {
+		t.Fatalf("update = %v, want %v", err, storage.ErrKeyNotFound)
+	}
+}
+
+func testList(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	mustAdd(t, ctx, s, "a", "1")
+	mustAdd(t, ctx, s, "b", "2")
+
+	items, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+
+	if len(items) != 2 || items["a"].Value != "1" || items["b"].Value != "2" {
+		t.Errorf("items = %v, want a=1 and b=2", items)
+	}
+}
+
+func testListEmpty(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	if _, err := s.List(ctx); !errors.Is(err, storage.ErrKeyNotFound) {
+		t.Fatalf("list = %v, want %v", err, storage.ErrKeyNotFound)
+	}
+}
+
+func testRemove(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	mustAdd(t, ctx, s, "a", "1")
+
+	if err := s.Remove(ctx, "a"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	if _, _, err := s.Get(ctx, "a"); !errors.Is(err, storage.ErrKeyNotFound) {
+		t.Fatalf("get = %v, want %v", err, storage.ErrKeyNotFound)
+	}
+
+	// a removed item can be added again
+	mustAdd(t, ctx, s, "a", "2")
+}
+
+func testRemoveMissing(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	if err := s.Remove(ctx, "missing"); !errors.Is(err, storage.ErrKeyNotFound) {
+		t.Fatalf("remove = %v, want %v", err, storage.ErrKeyNotFound)
+	}
+}
+
+func testVersions(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	mustAdd(t, ctx, s, "a", "1")
+
+	_, version, err := s.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if err = s.Update(storage.WithAuthor(ctx, "jane"), "a", Item{Value: "2"}, version); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	old, err := s.GetVersion(ctx, "a", version)
+	if err != nil {
+		t.Fatalf("get version: %v", err)
+	}
+
+	if old.Value != "1" {
+		t.Errorf("version %d value = %q, want %q", version, old.Value, "1")
+	}
+
+	versions, err := s.ListVersions(ctx, "a")
+	if err != nil {
+		t.Fatalf("list versions: %v", err)
+	}
+
+	if len(versions) != 2 {
+		t.Fatalf("versions = %d, want 2", len(versions))
+	}
+
+	if versions[0].Version >= versions[1].Version {
+		t.Errorf("versions are not sorted: %d, %d", versions[0].Version, versions[1].Version)
+	}
+
+	if versions[1].Author != "jane" {
+		t.Errorf("author = %q, want %q", versions[1].Author, "jane")
+	}
+
+	if _, err = s.GetVersion(ctx, "a", versions[1].Version+1); !errors.Is(err, storage.ErrKeyNotFound) {
+		t.Errorf("get version = %v, want %v", err, storage.ErrKeyNotFound)
+	}
+
+	if _, err = s.ListVersions(ctx, "missing"); !errors.Is(err, storage.ErrKeyNotFound) {
+		t.Errorf("list versions = %v, want %v", err, storage.ErrKeyNotFound)
+	}
+}
+
+func testNameWithSlash(t *testing.T, ctx context.Context, s storage.Storage[Item]) {
+	name := "arn:aws:eks:eu-west-1:123456789012:cluster/a"
+
+	mustAdd(t, ctx, s, name, "1")
+	mustValue(t, ctx, s, name, "1")
+
+	items, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+
+	if _, ok := items[name]; !ok {
+		t.Errorf("items = %v, want %q", items, name)
+	}
+}
+
+func mustAdd(t *testing.T, ctx context.Context, s storage.Storage[Item], name, value string) {
+	t.Helper()
+
+	if err := s.Add(ctx, name, Item{Value: value}); err != nil {
+		t.Fatalf("add %q: %v", name, err)
+	}
+}
+
+func mustValue(t *testing.T, ctx context.Context, s storage.Storage[Item], name, value string) {
+	t.Helper()
+
+	item, _, err := s.Get(ctx, name)
+	if err != nil {
+		t.Fatalf("get %q: %v", name, err)
+	}
+
+	if item.Value != value {
+		t.Errorf("%q value = %q, want %q", name, item.Value, value)
+	}
+}
@@ -0,0 +1,196 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	va "github.com/hashicorp/vault-client-go"
+	"github.com/hashicorp/vault-client-go/schema"
+)
+
+const (
+	AuthMethodToken      = "token"
+	AuthMethodAppRole    = "approle"
+	AuthMethodKubernetes = "kubernetes"
+
+	authRetryInterval = 10 * time.Second
+)
+
+// Auth logs in to vault and returns the client token with its lease.
+type Auth interface {
+	login(ctx context.Context, c *va.Client) (*lease, error)
+}
+
+type lease struct {
+	token     string
+	ttl       time.Duration
+	renewable bool
+}
+
+type tokenAuth struct {
+	token string
+}
+
+type appRoleAuth struct {
+	mount    string
+	roleID   string
+	secretID string
+}
+
+type kubernetesAuth struct {
+	mount     string
+	role      string
+	tokenPath string
+}
+
+// NewAuth returns the auth of the given method. When the mount is empty, the method is expected to be
+// mounted at its default path.
+func NewAuth(method, mount, token, roleID, secretID, role, tokenPath string) (Auth, error) {
+	if len(mount) == 0 {
+		mount = method
+	}
+
+	switch method {
+	case AuthMethodToken:
+		return &tokenAuth{token: token}, nil
+	case AuthMethodAppRole:
+		return &appRoleAuth{mount: mount, roleID: roleID, secretID: secretID}, nil
+	case AuthMethodKubernetes:
+		return &kubernetesAuth{mount: mount, role: role, tokenPath: tokenPath}, nil
+	default:
+		return nil, fmt.Errorf("vault auth method '%s' is not supported", method)
+	}
+}
+
+func (a *tokenAuth) login(ctx context.Context, c *va.Client) (*lease, error) {
+	res, err := c.Auth.TokenLookUpSelf(ctx, va.WithToken(a.token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up token: %w", err)
+	}
+
+	n, _ := res.Data["ttl"].(json.Number)
+	ttl, _ := n.Int64()
+	renewable, _ := res.Data["renewable"].(bool)
+
+	return &lease{
+		token:     a.token,
+		ttl:       time.Duration(ttl) * time.Second,
+		renewable: renewable,
+	}, nil
+}
+
+func (a *appRoleAuth) login(ctx context.Context, c *va.Client) (*lease, error) {
+	res, err := c.Auth.AppRoleLogin(ctx, schema.AppRoleLoginRequest{
+		RoleId:   a.roleID,
+		SecretId: a.secretID,
+	}, va.WithMountPath(a.mount))
+	if err != nil {
+		return nil, fmt.Errorf("failed to login with approle: %w", err)
+	}
+
+	return newLease(res.Auth)
+}
+
+func (a *kubernetesAuth) login(ctx context.Context, c *va.Client) (*lease, error) {
+	// projected service account tokens are rotated by the kubelet, so it is read on every login
+	jwt, err := os.ReadFile(a.tokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account token: %w", err)
+	}
+
+	res, err := c.Auth.KubernetesLogin(ctx, schema.KubernetesLoginRequest{
+		Jwt:  strings.TrimSpace(string(jwt)),
+		Role: a.role,
+	}, va.WithMountPath(a.mount))
+	if err != nil {
+		return nil, fmt.Errorf("failed to login with kubernetes: %w", err)
+	}
+
+	return newLease(res.Auth)
+}
+
+func newLease(auth *va.ResponseAuth) (*lease, error) {
+	if auth == nil || len(auth.ClientToken) == 0 {
+		return nil, errors.New("login response does not have a client token")
+	}
+
+	return &lease{
+		token:     auth.ClientToken,
+		ttl:       time.Duration(auth.LeaseDuration) * time.Second,
+		renewable: auth.Renewable,
+	}, nil
+}
+
+func (c *Client) login(ctx context.Context) (*lease, error) {
+	// login endpoints are unauthenticated, an expired token must not be sent to them
+	anonymous := c.Client.Clone()
+	anonymous.ClearToken()
+
+	l, err := c.auth.login(ctx, anonymous)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.SetToken(l.token); err != nil {
+		return nil, fmt.Errorf("failed to set token: %w", err)
+	}
+
+	return l, nil
+}
+
+// keepAlive renews the token lease before it expires and logs in again when the lease cannot be
+// renewed anymore. Tokens without a ttl never expire, so they are left as they are. A static token
+// cannot log in again, so keepAlive stops once it cannot be renewed.
+func (c *Client) keepAlive(ctx context.Context, l *lease) {
+	for l.ttl > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.ttl * 2 / 3):
+		}
+
+		if l.renewable {
+			res, err := c.Auth.TokenRenewSelf(ctx, schema.TokenRenewSelfRequest{})
+			if err == nil && res.Auth != nil && time.Duration(res.Auth.LeaseDuration)*time.Second >= l.ttl {
+				l.ttl = time.Duration(res.Auth.LeaseDuration) * time.Second
+				slog.Debug("vault token renewed", slog.Duration("ttl", l.ttl))
+
+				continue
+			}
+			if err != nil {
+				slog.Warn("failed to renew vault token", "error", err)
+			}
+		}
+
+		if _, ok := c.auth.(*tokenAuth); ok {
+			slog.Error("vault token cannot be renewed anymore and expires, a new token must be configured",
+				slog.Duration("expires_in", l.ttl/3))
+
+			return
+		}
+
+		for {
+			n, err := c.login(ctx)
+			if err == nil {
+				l = n
+				slog.Info("vault token re-authenticated", slog.Duration("ttl", l.ttl))
+
+				break
+			}
+
+			slog.Error("failed to re-authenticate vault", "error", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(authRetryInterval):
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	va "github.com/hashicorp/vault-client-go"
+	"github.com/jetbuild/engine/internal/storage"
+)
+
+// Migrate splits the legacy secret, which keeps every item of the key in a single 'items' map, into
+// one secret per item. Items which already exist in the per-item layout are left untouched and the
+// legacy secret is removed once every item is written, so running it more than once is a no-op.
+func Migrate[T any](ctx context.Context, client *Client, key string) error {
+	res, err := client.Secrets.KvV2Read(ctx, key, va.WithMountPath(client.engine))
+	if va.IsErrorStatus(err, http.StatusNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read legacy '%s' secret: %w", key, err)
+	}
+
+	v := &vault[T]{
+		client: client,
+		key:    key,
+	}
+
+	items, _ := res.Data.Data["items"].(map[string]any)
+
+	var migrated int
+
+	for name, item := range items {
+		data, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("legacy '%s' secret item '%s' is not an object", key, name)
+		}
+
+		model, dErr := decode[T](data)
+		if dErr != nil {
+			return fmt.Errorf("failed to decode legacy '%s' secret item '%s': %w", key, name, dErr)
+		}
+
+		err = v.Add(ctx, name, *model)
+		if errors.Is(err, storage.ErrItemAlreadyExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to migrate legacy '%s' secret item '%s': %w", key, name, err)
+		}
+
+		migrated++
+	}
+
+	if _, err = client.Secrets.KvV2DeleteMetadataAndAllVersions(ctx, key, va.WithMountPath(client.engine)); err != nil {
+		return fmt.Errorf("failed to remove legacy '%s' secret: %w", key, err)
+	}
+
+	slog.Info("legacy secret migrated", slog.String("key", key), slog.Int("items", migrated))
+
+	return nil
+}
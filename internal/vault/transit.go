@@ -0,0 +1,146 @@
+package vault
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	va "github.com/hashicorp/vault-client-go"
+	"github.com/hashicorp/vault-client-go/schema"
+)
+
+// Transit encrypts data with a data key generated by the transit engine, and keeps the data key
+// wrapped by the transit key next to the ciphertext. Envelopes are formatted as
+// '<wrapped data key>:<base64 nonce and ciphertext>'.
+type Transit struct {
+	client *Client
+	engine string
+	key    string
+}
+
+func NewTransit(client *Client, engine, key string) *Transit {
+	return &Transit{
+		client: client,
+		engine: engine,
+		key:    key,
+	}
+}
+
+func (t *Transit) Encrypt(ctx context.Context, plaintext []byte) (string, error) {
+	res, err := t.client.Secrets.TransitGenerateDataKey(ctx, t.key, "plaintext", schema.TransitGenerateDataKeyRequest{}, va.WithMountPath(t.engine))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate transit data key: %w", err)
+	}
+
+	wrapped, _ := res.Data["ciphertext"].(string)
+	encoded, _ := res.Data["plaintext"].(string)
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode transit data key: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+
+	return fmt.Sprintf("%s:%s", wrapped, base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+func (t *Transit) Decrypt(ctx context.Context, envelope string) ([]byte, error) {
+	wrapped, data, err := splitEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.client.Secrets.TransitDecrypt(ctx, t.key, schema.TransitDecryptRequest{
+		Ciphertext: wrapped,
+	}, va.WithMountPath(t.engine))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt transit data key: %w", err)
+	}
+
+	encoded, _ := res.Data["plaintext"].(string)
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transit data key: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("envelope ciphertext is too short")
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt envelope: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// Rewrap wraps the data key of the envelope with the latest version of the transit key, without
+// exposing the data key or the plaintext.
+func (t *Transit) Rewrap(ctx context.Context, envelope string) (string, error) {
+	wrapped, data, err := splitEnvelope(envelope)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := t.client.Secrets.TransitRewrap(ctx, t.key, schema.TransitRewrapRequest{
+		Ciphertext: wrapped,
+	}, va.WithMountPath(t.engine))
+	if err != nil {
+		return "", fmt.Errorf("failed to rewrap transit data key: %w", err)
+	}
+
+	rewrapped, _ := res.Data["ciphertext"].(string)
+
+	return fmt.Sprintf("%s:%s", rewrapped, base64.StdEncoding.EncodeToString(data)), nil
+}
+
+func splitEnvelope(envelope string) (string, []byte, error) {
+	i := strings.LastIndex(envelope, ":")
+	if i <= 0 {
+		return "", nil, errors.New("envelope is malformed")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(envelope[i+1:])
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+
+	return envelope[:i], data, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %w", err)
+	}
+
+	return gcm, nil
+}
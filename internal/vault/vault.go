@@ -1,33 +1,33 @@
-package vault // TODO: storage?
+package vault
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	va "github.com/hashicorp/vault-client-go"
 	"github.com/hashicorp/vault-client-go/schema"
+	"github.com/jetbuild/engine/internal/storage"
 )
 
-var (
-	ErrItemAlreadyExist = errors.New("item already exist")
-	ErrKeyNotFound      = errors.New("key not found")
-)
-
-type Vault[T any] interface {
-	Add(ctx context.Context, name string, model T) error
-	Get(ctx context.Context, name string) (*T, error)
-	List(ctx context.Context) (map[string]T, error)
-	Remove(ctx context.Context, name string) error
-	Update(ctx context.Context, name string, model T) error
-	Ping(ctx context.Context) error
-}
+// authorMetadataPrefix prefixes the custom metadata keys holding the author of each version.
+const authorMetadataPrefix = "author-"
 
 type Client struct {
 	*va.Client
 	engine string
+	auth   Auth
+	stop   context.CancelFunc
 }
 
 type vault[T any] struct {
@@ -35,27 +35,49 @@ type vault[T any] struct {
 	key    string
 }
 
-func New(ctx context.Context, addr, token, engine, description string) (*Client, error) {
-	c, err := va.New(va.WithAddress(addr))
+func New(ctx context.Context, addr string, auth Auth) (*Client, error) {
+	vc, err := va.New(va.WithAddress(addr))
 	if err != nil {
 		return nil, err
 	}
 
-	if err = c.SetToken(token); err != nil {
+	c := &Client{Client: vc, auth: auth}
+
+	l, err := c.login(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	if _, err = c.System.MountsEnableSecretsEngine(ctx, engine, schema.MountsEnableSecretsEngineRequest{
+	// the token outlives the given context, which only bounds the initialization
+	keepAliveCtx, stop := context.WithCancel(context.Background())
+	c.stop = stop
+
+	go c.keepAlive(keepAliveCtx, l)
+
+	return c, nil
+}
+
+// MountEngine enables the kv engine which keeps the repositories of the client, unless it is enabled already.
+// It is only required by the vault storage backend, the transit engine does not depend on it.
+func (c *Client) MountEngine(ctx context.Context, engine, description string) error {
+	if _, err := c.System.MountsEnableSecretsEngine(ctx, engine, schema.MountsEnableSecretsEngineRequest{
 		Description: description,
 		Type:        "kv",
 	}); err != nil && !strings.Contains(err.Error(), "path is already in use") {
-		return nil, err
+		return err
 	}
 
-	return &Client{Client: c, engine: engine}, nil
+	c.engine = engine
+
+	return nil
 }
 
-func NewRepository[T any](client *Client, key string) Vault[T] {
+// Close stops renewing the token of the client.
+func (c *Client) Close() {
+	c.stop()
+}
+
+func NewRepository[T any](client *Client, key string) storage.Storage[T] {
 	return &vault[T]{
 		client: client,
 		key:    key,
@@ -63,126 +85,275 @@ func NewRepository[T any](client *Client, key string) Vault[T] {
 }
 
 func (v *vault[T]) Add(ctx context.Context, name string, model T) error {
-	items, err := v.List(ctx)
-	if err != nil && !errors.Is(err, ErrKeyNotFound) {
-		return err
+	err := v.write(ctx, name, model, 0)
+	if errors.Is(err, storage.ErrConflict) {
+		return storage.ErrItemAlreadyExist
 	}
 
-	if items == nil {
-		items = make(map[string]T)
+	return err
+}
+
+func (v *vault[T]) Get(ctx context.Context, name string) (*T, int64, error) {
+	res, err := v.client.Secrets.KvV2Read(ctx, v.path(name), va.WithMountPath(v.client.engine))
+	if va.IsErrorStatus(err, http.StatusNotFound) {
+		return nil, 0, storage.ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if _, ok := items[name]; ok {
-		return ErrItemAlreadyExist
+	if len(res.Data.Data) == 0 {
+		return nil, 0, storage.ErrKeyNotFound
 	}
 
-	items[name] = model
+	version, err := metadataVersion(res.Data.Metadata)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	if _, err = v.client.Secrets.KvV2Write(ctx, v.key, schema.KvV2WriteRequest{
-		Data: map[string]any{
-			"items": items,
-		},
-	}, va.WithMountPath(v.client.engine)); err != nil {
-		return err
+	item, err := decode[T](res.Data.Data)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return nil
+	return item, version, nil
 }
 
-func (v *vault[T]) Get(ctx context.Context, name string) (*T, error) {
-	items, err := v.List(ctx)
+func (v *vault[T]) List(ctx context.Context) (map[string]T, error) {
+	res, err := v.client.Secrets.KvV2List(ctx, v.key, va.WithMountPath(v.client.engine))
+	if va.IsErrorStatus(err, http.StatusNotFound) {
+		return nil, storage.ErrKeyNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	for k, i := range items {
-		if k == name {
-			return &i, nil
+	items := make(map[string]T)
+
+	for _, key := range res.Data.Keys {
+		if strings.HasSuffix(key, "/") {
+			continue
+		}
+
+		name, uErr := url.PathUnescape(key)
+		if uErr != nil {
+			return nil, fmt.Errorf("item key '%s' is invalid: %w", key, uErr)
+		}
+
+		item, _, gErr := v.Get(ctx, name)
+		if errors.Is(gErr, storage.ErrKeyNotFound) {
+			continue
 		}
+		if gErr != nil {
+			return nil, gErr
+		}
+
+		items[name] = *item
 	}
 
-	return nil, ErrKeyNotFound
-}
+	if len(items) == 0 {
+		return nil, storage.ErrKeyNotFound
+	}
 
-func (v *vault[T]) List(ctx context.Context) (map[string]T, error) {
-	items := make(map[string]T)
+	return items, nil
+}
 
-	res, err := v.client.Secrets.KvV2Read(ctx, v.key, va.WithMountPath(v.client.engine))
-	if err != nil && err.(*va.ResponseError).StatusCode != http.StatusNotFound {
-		return nil, err
+func (v *vault[T]) Remove(ctx context.Context, name string) error {
+	if _, _, err := v.Get(ctx, name); err != nil {
+		return err
 	}
 
-	if err != nil && err.(*va.ResponseError).StatusCode == http.StatusNotFound {
-		return nil, ErrKeyNotFound
+	if _, err := v.client.Secrets.KvV2DeleteMetadataAndAllVersions(ctx, v.path(name), va.WithMountPath(v.client.engine)); err != nil {
+		return err
 	}
 
-	if len(res.Data.Data) == 0 || len(res.Data.Data["items"].(map[string]any)) == 0 {
-		return nil, ErrKeyNotFound
+	return nil
+}
+
+// Update writes the item only if its current version still equals to the given version, which is
+// returned by Get. Otherwise, storage.ErrConflict is returned.
+func (v *vault[T]) Update(ctx context.Context, name string, model T, version int64) error {
+	if _, _, err := v.Get(ctx, name); err != nil {
+		return err
 	}
 
-	m, err := json.Marshal(res.Data.Data["items"])
+	return v.write(ctx, name, model, version)
+}
+
+func (v *vault[T]) GetVersion(ctx context.Context, name string, version int64) (*T, error) {
+	res, err := v.client.Secrets.KvV2Read(ctx, v.path(name), va.WithMountPath(v.client.engine), va.WithQueryParameters(url.Values{
+		"version": []string{strconv.FormatInt(version, 10)},
+	}))
+	if va.IsErrorStatus(err, http.StatusNotFound) {
+		return nil, storage.ErrKeyNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if err = json.Unmarshal(m, &items); err != nil {
+	if len(res.Data.Data) == 0 {
+		return nil, storage.ErrKeyNotFound
+	}
+
+	return decode[T](res.Data.Data)
+}
+
+func (v *vault[T]) ListVersions(ctx context.Context, name string) ([]storage.Version, error) {
+	res, err := v.client.Secrets.KvV2ReadMetadata(ctx, v.path(name), va.WithMountPath(v.client.engine))
+	if va.IsErrorStatus(err, http.StatusNotFound) {
+		return nil, storage.ErrKeyNotFound
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	return items, nil
+	versions := make([]storage.Version, 0, len(res.Data.Versions))
+
+	for k, m := range res.Data.Versions {
+		version, pErr := strconv.ParseInt(k, 10, 64)
+		if pErr != nil {
+			return nil, fmt.Errorf("item version '%s' is invalid: %w", k, pErr)
+		}
+
+		metadata, _ := m.(map[string]any)
+		created, _ := metadata["created_time"].(string)
+		deleted, _ := metadata["deletion_time"].(string)
+		destroyed, _ := metadata["destroyed"].(bool)
+
+		t, pErr := time.Parse(time.RFC3339Nano, created)
+		if pErr != nil {
+			return nil, fmt.Errorf("item version '%s' created time is invalid: %w", k, pErr)
+		}
+
+		author, _ := res.Data.CustomMetadata[authorMetadataPrefix+k].(string)
+
+		versions = append(versions, storage.Version{
+			Version:   version,
+			Created:   t,
+			Author:    author,
+			Deleted:   len(deleted) > 0,
+			Destroyed: destroyed,
+		})
+	}
+
+	slices.SortFunc(versions, func(a, b storage.Version) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return versions, nil
 }
 
-func (v *vault[T]) Remove(ctx context.Context, name string) error {
-	items, err := v.List(ctx)
+func (v *vault[T]) Ping(ctx context.Context) error {
+	_, err := v.client.System.ReadHealthStatus(ctx, va.WithMountPath(v.client.engine))
 	if err != nil {
 		return err
 	}
 
-	if _, ok := items[name]; !ok {
-		return ErrKeyNotFound
-	}
+	return nil
+}
+
+// path returns the secret path of the item. Names are escaped, so that a name containing "/" is not nested
+// into a sub-path, which List would skip as a folder.
+func (v *vault[T]) path(name string) string {
+	return path.Join(v.key, url.PathEscape(name))
+}
 
-	delete(items, name)
+// write stores the item with check-and-set, where a zero version only allows to create the item.
+func (v *vault[T]) write(ctx context.Context, name string, model T, version int64) error {
+	data, err := encode(model)
+	if err != nil {
+		return err
+	}
 
-	if _, err = v.client.Secrets.KvV2Write(ctx, v.key, schema.KvV2WriteRequest{
-		Data: map[string]any{
-			"items": items,
+	res, err := v.client.Secrets.KvV2Write(ctx, v.path(name), schema.KvV2WriteRequest{
+		Data: data,
+		Options: map[string]any{
+			"cas": version,
 		},
-	}, va.WithMountPath(v.client.engine)); err != nil {
+	}, va.WithMountPath(v.client.engine))
+	if va.IsErrorStatus(err, http.StatusBadRequest) && strings.Contains(err.Error(), "check-and-set") {
+		return storage.ErrConflict
+	}
+	if err != nil {
 		return err
 	}
 
+	if author := storage.Author(ctx); len(author) > 0 {
+		// the item is written already, so a failure to record its author does not fail the write
+		if aErr := v.writeAuthor(ctx, name, res.Data.Version, author); aErr != nil {
+			slog.Warn("failed to record item version author", "key", v.key, "name", name, "error", aErr)
+		}
+	}
+
 	return nil
 }
 
-func (v *vault[T]) Update(ctx context.Context, name string, model T) error {
-	items, err := v.List(ctx)
+// writeAuthor records the author of the version in the custom metadata of the secret, which is shared by
+// all of its versions. Authors of the versions which no longer exist are dropped, as the custom metadata
+// is limited in size.
+func (v *vault[T]) writeAuthor(ctx context.Context, name string, version int64, author string) error {
+	res, err := v.client.Secrets.KvV2ReadMetadata(ctx, v.path(name), va.WithMountPath(v.client.engine))
 	if err != nil {
 		return err
 	}
 
-	if _, ok := items[name]; !ok {
-		return ErrKeyNotFound
+	custom := make(map[string]any)
+	for k, a := range res.Data.CustomMetadata {
+		n, ok := strings.CutPrefix(k, authorMetadataPrefix)
+		if !ok {
+			custom[k] = a
+			continue
+		}
+
+		if _, exist := res.Data.Versions[n]; exist {
+			custom[k] = a
+		}
 	}
 
-	items[name] = model
+	custom[authorMetadataPrefix+strconv.FormatInt(version, 10)] = author
 
-	if _, err = v.client.Secrets.KvV2Write(ctx, v.key, schema.KvV2WriteRequest{
-		Data: map[string]any{
-			"items": items,
-		},
-	}, va.WithMountPath(v.client.engine)); err != nil {
-		return err
+	_, err = v.client.Secrets.KvV2WriteMetadata(ctx, v.path(name), schema.KvV2WriteMetadataRequest{
+		CustomMetadata: custom,
+	}, va.WithMountPath(v.client.engine))
+
+	return err
+}
+
+func metadataVersion(metadata map[string]any) (int64, error) {
+	switch v := metadata["version"].(type) {
+	case json.Number:
+		return v.Int64()
+	case float64:
+		return int64(v), nil
+	default:
+		return 0, fmt.Errorf("item metadata version '%v' is invalid", metadata["version"])
 	}
+}
 
-	return nil
+func encode[T any](model T) (map[string]any, error) {
+	m, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal item: %w", err)
+	}
+
+	var data map[string]any
+	if err = json.Unmarshal(m, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal item: %w", err)
+	}
+
+	return data, nil
 }
 
-func (v *vault[T]) Ping(ctx context.Context) error {
-	_, err := v.client.System.ReadHealthStatus(ctx, va.WithMountPath(v.client.engine))
+func decode[T any](data map[string]any) (*T, error) {
+	m, err := json.Marshal(data)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to marshal item: %w", err)
 	}
 
-	return nil
+	var item T
+	if err = json.Unmarshal(m, &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal item: %w", err)
+	}
+
+	return &item, nil
 }
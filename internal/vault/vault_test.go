@@ -0,0 +1,49 @@
+package vault_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jetbuild/engine/internal/storage"
+	"github.com/jetbuild/engine/internal/storage/storagetest"
+	"github.com/jetbuild/engine/internal/vault"
+)
+
+// TestVault runs the storage suite against the vault server of VAULT_TEST_ADDR, such as a server started
+// with 'vault server -dev', and is skipped without it.
+func TestVault(t *testing.T) {
+	addr := os.Getenv("VAULT_TEST_ADDR")
+	if len(addr) == 0 {
+		t.Skip("VAULT_TEST_ADDR is not set")
+	}
+
+	auth, err := vault.NewAuth(vault.AuthMethodToken, "", os.Getenv("VAULT_TEST_TOKEN"), "", "", "", "")
+	if err != nil {
+		t.Fatalf("new auth: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	c, err := vault.New(ctx, addr, auth)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	t.Cleanup(c.Close)
+
+	if err = c.MountEngine(ctx, "jetbuild-test", ""); err != nil {
+		t.Fatalf("mount engine: %v", err)
+	}
+
+	run := time.Now().UnixNano()
+	i := 0
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage[storagetest.Item] {
+		i++
+
+		return vault.NewRepository[storagetest.Item](c, fmt.Sprintf("test-%d-%d", run, i))
+	})
+}